@@ -10,21 +10,24 @@ import (
 	policy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
 
 	annotatorconfig "github.com/gocrane/crane-scheduler/pkg/controller/annotator/config"
-	prom "github.com/gocrane/crane-scheduler/pkg/controller/prometheus"
+	deschedulerconfig "github.com/gocrane/crane-scheduler/pkg/controller/descheduler/config"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
 )
 
 // Config is the main context object for crane scheduler controller.
 type Config struct {
 	// AnnotatorConfig holds configuration for a node annotator.
 	AnnotatorConfig *annotatorconfig.AnnotatorConfiguration
+	// DeschedulerConfig holds configuration for a load-aware descheduler.
+	DeschedulerConfig *deschedulerconfig.DeschedulerConfiguration
 	// LeaderElection holds configuration for leader election.
 	LeaderElection *componentbaseconfig.LeaderElectionConfiguration
 	// KubeInformerFactory gives access to kubernetes informers for the controller.
 	KubeInformerFactory informers.SharedInformerFactory
 	// KubeClient is the general kube client.
 	KubeClient clientset.Interface
-	// PromClient is used for getting metric data from Prometheus.
-	PromClient prom.PromClient
+	// MetricsProvider is used for getting metric data of nodes.
+	MetricsProvider provider.MetricsProvider
 	// Policy is a collection of scheduler policies.
 	Policy *policy.DynamicSchedulerPolicy
 	// EventRecorder is the event sink
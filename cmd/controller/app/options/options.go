@@ -5,17 +5,28 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	componentbaseconfig "k8s.io/component-base/config"
 	options "k8s.io/component-base/config/options"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	controllerappconfig "github.com/gocrane/crane-scheduler/cmd/controller/app/config"
+	"github.com/gocrane/crane-scheduler/pkg/controller/annotator"
 	annotatorconfig "github.com/gocrane/crane-scheduler/pkg/controller/annotator/config"
+	deschedulerconfig "github.com/gocrane/crane-scheduler/pkg/controller/descheduler/config"
+	"github.com/gocrane/crane-scheduler/pkg/controller/metricsserver"
 	"github.com/gocrane/crane-scheduler/pkg/controller/prometheus"
-	dynamicscheduler "github.com/gocrane/crane-scheduler/pkg/plugins/dynamic"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	policyscheme "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy/scheme"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy/validation"
 	utils "github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
@@ -27,6 +38,8 @@ const (
 type Options struct {
 	*annotatorconfig.AnnotatorConfiguration
 
+	Descheduler *deschedulerconfig.DeschedulerConfiguration
+
 	LeaderElection *componentbaseconfig.LeaderElectionConfiguration
 
 	master     string
@@ -41,6 +54,19 @@ func NewOptions() (*Options, error) {
 			BindingHeapSize:  1024,
 			ConcurrentSyncs:  1,
 			PolicyConfigPath: "/etc/kubernetes/policy.yaml",
+			MetricsProvider:  string(provider.PrometheusProvider),
+			AnnotationFormat: string(annotator.LegacyAnnotationFormat),
+			TimestampFormat:  string(utils.LegacyTimestampFormat),
+			BindingSource:    string(annotator.EventBindingSource),
+
+			MetricsServerPollInterval: metricsserver.DefaultPollInterval,
+		},
+		Descheduler: &deschedulerconfig.DeschedulerConfiguration{
+			Interval:            time.Minute,
+			HighWatermark:       1,
+			LowWatermark:        0.8,
+			SustainedPeriod:     5 * time.Minute,
+			MaxEvictionsPerNode: 1,
 		},
 		LeaderElection: &componentbaseconfig.LeaderElectionConfiguration{
 			LeaderElect:       true,
@@ -65,11 +91,24 @@ func (o *Options) Flags(flag *pflag.FlagSet) error {
 
 	flag.StringVar(&o.PolicyConfigPath, "policy-config-path", o.PolicyConfigPath, "Path to annotator policy cofig")
 	flag.StringVar(&o.PrometheusAddr, "prometheus-address", o.PrometheusAddr, "The address of prometheus, from which we can pull metrics data.")
+	flag.StringVar(&o.MetricsProvider, "metrics-provider", o.MetricsProvider, "The backend from which we pull metrics data, one of prometheus, metrics-server and static.")
+	flag.DurationVar(&o.MetricsServerPollInterval, "metrics-server-poll-interval", o.MetricsServerPollInterval, "The interval of polling node metrics from metrics-server, used only by the metrics-server provider.")
+	flag.BoolVar(&o.BatchSync, "batch-sync", o.BatchSync, "Query one metric of all nodes at once if supported by the metrics provider, and only patch nodes whose value changed.")
+	flag.StringVar(&o.AnnotationFormat, "annotation-format", o.AnnotationFormat, "The format in which node load is stored, legacy for one annotation per metric, structured for one JSON annotation holding all metrics.")
+	flag.StringVar(&o.TimestampFormat, "timestamp-format", o.TimestampFormat, "The format of timestamps in per-metric annotations, one of legacy, rfc3339 and unix. The legacy format depends on the TZ of both the controller and the scheduler, while the others require schedulers which accept them.")
+	flag.StringVar(&o.StaticMetricsPath, "static-metrics-path", o.StaticMetricsPath, "Path to metrics data file, used only by the static metrics provider.")
+	flag.StringVar(&o.BindingSource, "binding-source", o.BindingSource, "Where bindings of pods to compute hot values come from, event for messages of Scheduled events, or pod for watching spec.nodeName of pods.")
 	flag.Int32Var(&o.BindingHeapSize, "binding-heap-size", o.BindingHeapSize, "Max size of binding heap size, used to store hot value data.")
 	flag.Int32Var(&o.ConcurrentSyncs, "concurrent-syncs", o.ConcurrentSyncs, "The number of annotator controller workers that are allowed to sync concurrently.")
+	flag.BoolVar(&o.Descheduler.Enabled, "descheduler-enabled", o.Descheduler.Enabled, "Evict opted-in pods from nodes overloaded for a sustained period, so that they are rescheduled to nodes with lower load.")
+	flag.DurationVar(&o.Descheduler.Interval, "descheduler-interval", o.Descheduler.Interval, "How often the descheduler checks the load of nodes.")
+	flag.Float64Var(&o.Descheduler.HighWatermark, "descheduler-high-watermark", o.Descheduler.HighWatermark, "The ratio to thresholds of predicates, above which nodes are overloaded.")
+	flag.Float64Var(&o.Descheduler.LowWatermark, "descheduler-low-watermark", o.Descheduler.LowWatermark, "The ratio to thresholds of predicates, below which nodes can take evicted pods.")
+	flag.DurationVar(&o.Descheduler.SustainedPeriod, "descheduler-sustained-period", o.Descheduler.SustainedPeriod, "How long nodes stay overloaded before pods are evicted from them.")
+	flag.Int32Var(&o.Descheduler.MaxEvictionsPerNode, "descheduler-max-evictions-per-node", o.Descheduler.MaxEvictionsPerNode, "The max number of pods evicted from one node each time.")
 	flag.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to kubeconfig file with authorization information")
 	flag.StringVar(&o.master, "master", o.master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
-	flag.StringVar(&o.healthPort, "health-port", o.healthPort, "The port of health check")
+	flag.StringVar(&o.healthPort, "health-port", o.healthPort, "The port of health check and metrics")
 
 	options.BindLeaderElectionFlags(o.LeaderElection, flag)
 	return nil
@@ -78,12 +117,62 @@ func (o *Options) Flags(flag *pflag.FlagSet) error {
 // ApplyTo fills up Annotator config with options.
 func (o *Options) ApplyTo(c *controllerappconfig.Config) error {
 	c.AnnotatorConfig = o.AnnotatorConfiguration
+	c.DeschedulerConfig = o.Descheduler
 	c.LeaderElection = o.LeaderElection
 	return nil
 }
 
 // Validate validates the options and config before launching Annotator.
 func (o *Options) Validate() error {
+	switch provider.ProviderType(o.MetricsProvider) {
+	case provider.PrometheusProvider, provider.MetricsServerProvider:
+	case provider.StaticProvider:
+		if o.StaticMetricsPath == "" {
+			return fmt.Errorf("static-metrics-path must be specified for static metrics provider")
+		}
+	default:
+		return fmt.Errorf("unsupported metrics provider %q", o.MetricsProvider)
+	}
+
+	switch annotator.AnnotationFormat(o.AnnotationFormat) {
+	case annotator.LegacyAnnotationFormat, annotator.StructuredAnnotationFormat:
+	default:
+		return fmt.Errorf("unsupported annotation format %q", o.AnnotationFormat)
+	}
+
+	switch utils.TimestampFormat(o.TimestampFormat) {
+	case utils.RFC3339TimestampFormat, utils.UnixTimestampFormat, utils.LegacyTimestampFormat:
+	default:
+		return fmt.Errorf("unsupported timestamp format %q", o.TimestampFormat)
+	}
+
+	switch annotator.BindingSource(o.BindingSource) {
+	case annotator.EventBindingSource, annotator.PodBindingSource:
+	default:
+		return fmt.Errorf("unsupported binding source %q", o.BindingSource)
+	}
+
+	if o.Descheduler.Enabled {
+		if o.Descheduler.Interval <= 0 {
+			return fmt.Errorf("descheduler-interval must be greater than 0")
+		}
+		if o.Descheduler.LowWatermark <= 0 || o.Descheduler.LowWatermark >= o.Descheduler.HighWatermark {
+			return fmt.Errorf("descheduler-low-watermark must be greater than 0 and less than descheduler-high-watermark")
+		}
+		if o.Descheduler.MaxEvictionsPerNode <= 0 {
+			return fmt.Errorf("descheduler-max-evictions-per-node must be greater than 0")
+		}
+	}
+
+	p, err := policyscheme.LoadPolicyFromFile(o.PolicyConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load policy config file: %v", err)
+	}
+
+	if errs := validation.ValidateDynamicSchedulerPolicy(p); len(errs) > 0 {
+		return fmt.Errorf("invalid policy config file %s: %v", o.PolicyConfigPath, errs.ToAggregate())
+	}
+
 	return nil
 }
 
@@ -101,7 +190,7 @@ func (o *Options) Config() (*controllerappconfig.Config, error) {
 		return nil, err
 	}
 
-	c.Policy, err = dynamicscheduler.LoadPolicyFromFile(o.PolicyConfigPath)
+	c.Policy, err = policyscheme.LoadPolicyFromFile(o.PolicyConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +212,11 @@ func (o *Options) Config() (*controllerappconfig.Config, error) {
 
 	c.LeaderElectionClient = clientset.NewForConfigOrDie(rest.AddUserAgent(kubeconfig, "leader-election"))
 
-	c.PromClient, err = prometheus.NewPromClient(o.PrometheusAddr)
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.KubeClient.CoreV1().Events("")})
+	c.EventRecorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: ControllerUserAgent})
+
+	c.MetricsProvider, err = o.newMetricsProvider(kubeconfig, c.Policy)
 	if err != nil {
 		return nil, err
 	}
@@ -134,3 +227,27 @@ func (o *Options) Config() (*controllerappconfig.Config, error) {
 
 	return c, nil
 }
+
+// newMetricsProvider creates the MetricsProvider specified by options.
+func (o *Options) newMetricsProvider(kubeconfig *rest.Config, p *policy.DynamicSchedulerPolicy) (provider.MetricsProvider, error) {
+	switch provider.ProviderType(o.MetricsProvider) {
+	case provider.MetricsServerProvider:
+		metricsClient, err := metricsclientset.NewForConfig(rest.AddUserAgent(kubeconfig, ControllerUserAgent))
+		if err != nil {
+			return nil, err
+		}
+		var metricNames []string
+		for _, syncPolicy := range policy.GetAllSyncPolicies(&p.Spec) {
+			metricNames = append(metricNames, syncPolicy.Name)
+		}
+		return metricsserver.NewMetricsProvider(metricsClient, o.MetricsServerPollInterval, metricNames)
+	case provider.StaticProvider:
+		return provider.NewStaticProviderFromFile(o.StaticMetricsPath)
+	default:
+		promClient, err := prometheus.NewPromClient(o.PrometheusAddr)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.NewMetricsProvider(promClient, policy.GetAllSyncPolicies(&p.Spec))
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
 	"github.com/gocrane/crane-scheduler/cmd/controller/app/config"
 	"github.com/gocrane/crane-scheduler/cmd/controller/app/options"
 	"github.com/gocrane/crane-scheduler/pkg/controller/annotator"
+	"github.com/gocrane/crane-scheduler/pkg/controller/descheduler"
 )
 
 // NewControllerCommand creates a *cobra.Command object with default parameters
@@ -64,19 +66,37 @@ func Run(cc *config.CompletedConfig, stopCh <-chan struct{}) error {
 		annotatorController := annotator.NewNodeAnnotator(
 			cc.KubeInformerFactory.Core().V1().Nodes(),
 			cc.KubeInformerFactory.Core().V1().Events(),
+			cc.KubeInformerFactory.Core().V1().Pods(),
 			cc.KubeClient,
-			cc.PromClient,
+			cc.MetricsProvider,
 			*cc.Policy,
-			cc.AnnotatorConfig.BindingHeapSize,
+			cc.AnnotatorConfig,
 		)
 
+		if cc.DeschedulerConfig.Enabled {
+			deschedulerController := descheduler.NewDescheduler(
+				cc.KubeInformerFactory.Core().V1().Nodes(),
+				cc.KubeInformerFactory.Core().V1().Pods(),
+				cc.KubeClient,
+				cc.EventRecorder,
+				*cc.Policy,
+				cc.DeschedulerConfig,
+			)
+			go func() {
+				if err := deschedulerController.Run(stopCh); err != nil {
+					klog.Errorf("Descheduler stopped: %v", err)
+				}
+			}()
+		}
+
 		cc.KubeInformerFactory.Start(stopCh)
 
-		panic(annotatorController.Run(int(cc.AnnotatorConfig.ConcurrentSyncs), stopCh))
+		panic(annotatorController.Run(stopCh))
 	}
 
 	healthMux := http.NewServeMux()
 	healthz.InstallHandler(healthMux, healthz.NamedCheck("crane-scheduler-controller", healthz.PingHealthz.Check))
+	healthMux.Handle("/metrics", legacyregistry.Handler())
 	go func() {
 		if err := http.ListenAndServe(fmt.Sprintf(":%s", cc.HealthPort), healthMux); err != nil {
 			klog.Fatal("failed to listen & server health server from port %s: %v", cc.HealthPort, err)
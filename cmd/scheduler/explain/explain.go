@@ -0,0 +1,221 @@
+package explain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy/validation"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/dynamic"
+)
+
+// Options has all the params needed to explain scheduling decisions.
+type Options struct {
+	PolicyConfigPath string
+	Pod              string
+	Nodes            []string
+	Output           string
+
+	master     string
+	kubeconfig string
+}
+
+// NewExplainCommand creates a *cobra.Command object which shows how the Dynamic
+// plugin filters and scores nodes for a pod with the current node annotations.
+func NewExplainCommand() *cobra.Command {
+	o := &Options{
+		PolicyConfigPath: "/etc/kubernetes/policy.yaml",
+		Output:           "text",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explain why nodes are filtered or how they are scored by the Dynamic plugin",
+		Long: `Explain reads load annotations of nodes and evaluates the dynamic scheduler policy
+		against them, showing each predicate's usage versus threshold, each priority's
+		contribution, the hot value penalty and the estimated score of every node. Inputs
+		only known by the running scheduler, such as bindings it made since the hot value
+		is annotated, are listed as not included.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.OutOrStdout())
+		},
+	}
+
+	flag := cmd.Flags()
+	flag.StringVar(&o.PolicyConfigPath, "policy-config-path", o.PolicyConfigPath, "Path to scheduler policy config")
+	flag.StringVar(&o.Pod, "pod", o.Pod, "The pod to schedule, in the form of [namespace/]name")
+	flag.StringSliceVar(&o.Nodes, "nodes", o.Nodes, "Names of nodes to explain, all nodes if not specified")
+	flag.StringVarP(&o.Output, "output", "o", o.Output, "Output format, one of text and json")
+	flag.StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to kubeconfig file with authorization information")
+	flag.StringVar(&o.master, "master", o.master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
+
+	// do not inherit the usage of kube-scheduler flags.
+	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		fmt.Fprintf(cmd.OutOrStderr(), "Usage:\n  %s [flags]\n\nFlags:\n%s", cmd.CommandPath(), cmd.Flags().FlagUsages())
+		return nil
+	})
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n", cmd.Long)
+		cmd.Usage()
+	})
+
+	return cmd
+}
+
+// Validate validates the options.
+func (o *Options) Validate() error {
+	if o.Pod == "" {
+		return fmt.Errorf("pod must be specified")
+	}
+
+	if o.Output != "text" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %q", o.Output)
+	}
+
+	return nil
+}
+
+// Run explains scheduling decisions and writes them to out.
+func (o *Options) Run(out io.Writer) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	p, err := dynamic.LoadPolicyFromFile(o.PolicyConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load policy config file: %v", err)
+	}
+
+	if errs := validation.ValidateDynamicSchedulerPolicy(p); len(errs) > 0 {
+		return fmt.Errorf("invalid policy config file %s: %v", o.PolicyConfigPath, errs.ToAggregate())
+	}
+
+	var kubeconfig *rest.Config
+	if o.kubeconfig == "" {
+		kubeconfig, err = rest.InClusterConfig()
+	} else {
+		kubeconfig, err = clientcmd.BuildConfigFromFlags(o.master, o.kubeconfig)
+	}
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := clientset.NewForConfig(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	namespace, name := metav1.NamespaceDefault, o.Pod
+	if parts := strings.SplitN(o.Pod, "/", 2); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	}
+
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod %s/%s: %v", namespace, name, err)
+	}
+
+	var nodes []*v1.Node
+	if len(o.Nodes) == 0 {
+		nodeList, err := kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list nodes: %v", err)
+		}
+		for i := range nodeList.Items {
+			nodes = append(nodes, &nodeList.Items[i])
+		}
+	} else {
+		for _, nodeName := range o.Nodes {
+			node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get node %s: %v", nodeName, err)
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	explanations := dynamic.Explain(pod, nodes, p.Spec)
+
+	if o.Output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanations)
+	}
+
+	return printExplanations(out, explanations)
+}
+
+// printExplanations writes explanations in a human readable way.
+func printExplanations(out io.Writer, explanations []*dynamic.NodeExplanation) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	for _, e := range explanations {
+		status := "schedulable"
+		if e.Filtered {
+			status = "filtered"
+		}
+		if e.Reason != "" {
+			status += ": " + e.Reason
+		}
+
+		if e.Override != "" {
+			status += ", policy override " + e.Override
+		}
+		if e.NodePool != "" {
+			status += ", node pool " + e.NodePool
+		}
+
+		fmt.Fprintf(w, "Node %s (%s)\n", e.NodeName, status)
+		if e.IgnoreLoad {
+			fmt.Fprintf(w, "  load is ignored, score %d\n\n", e.EstimatedScore)
+			continue
+		}
+
+		fmt.Fprintf(w, "  PREDICATE\tUSAGE\tTHRESHOLD\tOVERLOAD\tNOTE\n")
+		for _, p := range e.Predicates {
+			fmt.Fprintf(w, "  %s\t%.4f\t%.4f\t%t\t%s\n", p.Name, p.Usage, p.Threshold, p.Overload, note(p.Stale, p.Error, p.MissingDataAction))
+		}
+
+		fmt.Fprintf(w, "  PRIORITY\tUSAGE\tWEIGHT\tCONTRIBUTION\tNOTE\n")
+		for _, p := range e.Priorities {
+			fmt.Fprintf(w, "  %s\t%.4f\t%.4f\t%.2f\t%s\n", p.Name, p.Usage, p.Weight, p.Contribution, note(p.Stale, p.Error, p.MissingDataAction))
+		}
+
+		penalized := ""
+		if e.Penalized {
+			penalized = ", penalized for missing data"
+		}
+		normalized := ""
+		if e.NormalizedScore != e.EstimatedScore {
+			normalized = fmt.Sprintf(", normalized score %d", e.NormalizedScore)
+		}
+		fmt.Fprintf(w, "  score %d, hot value %.2f (penalty %d)%s, estimated score %d%s\n", e.Score, e.HotValue, e.HotValuePenalty, penalized, e.EstimatedScore, normalized)
+		if len(e.Unavailable) > 0 {
+			fmt.Fprintf(w, "  not included, only known by the running scheduler: %s\n", strings.Join(e.Unavailable, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	return w.Flush()
+}
+
+func note(stale bool, err string, action policy.MissingDataAction) string {
+	if err == "" {
+		return ""
+	}
+	if stale {
+		err = "stale: " + err
+	}
+	return fmt.Sprintf("%s, take action %s", err, action)
+}
@@ -9,6 +9,7 @@ import (
 	"k8s.io/component-base/logs"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 
+	"github.com/gocrane/crane-scheduler/cmd/scheduler/explain"
 	_ "github.com/gocrane/crane-scheduler/pkg/plugins/apis/config/scheme"
 
 	"github.com/gocrane/crane-scheduler/pkg/plugins/dynamic"
@@ -21,6 +22,7 @@ func main() {
 		app.WithPlugin(dynamic.Name, dynamic.NewDynamicScheduler),
 		app.WithPlugin(noderesourcetopology.Name, noderesourcetopology.New),
 	)
+	cmd.AddCommand(explain.NewExplainCommand())
 
 	logs.InitLogs()
 	defer logs.FlushLogs()
@@ -33,6 +33,7 @@ replace (
 
 require (
 	github.com/evanphx/json-patch v4.12.0+incompatible
+	github.com/fsnotify/fsnotify v1.5.1
 	github.com/gocrane/api v0.7.1-0.20220819080332-e4c0d60e812d
 	github.com/prometheus/client_golang v1.12.1
 	github.com/prometheus/common v0.33.0
@@ -47,6 +48,8 @@ require (
 	k8s.io/klog/v2 v2.60.1
 	k8s.io/kube-scheduler v0.23.3
 	k8s.io/kubernetes v1.23.3
+	k8s.io/metrics v0.23.3
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -64,7 +67,6 @@ require (
 	github.com/docker/distribution v2.8.1+incompatible // indirect
 	github.com/emicklei/go-restful v2.9.5+incompatible // indirect
 	github.com/felixge/httpsnoop v1.0.1 // indirect
-	github.com/fsnotify/fsnotify v1.5.1 // indirect
 	github.com/go-logr/logr v1.2.0 // indirect
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
 	github.com/go-openapi/jsonreference v0.19.5 // indirect
@@ -138,11 +140,9 @@ require (
 	k8s.io/csi-translation-lib v0.23.3 // indirect
 	k8s.io/gengo v0.0.0-20211129171323-c02415ce4185 // indirect
 	k8s.io/kube-openapi v0.0.0-20211115234752-e816edb12b65 // indirect
-	k8s.io/metrics v0.23.3 // indirect
 	k8s.io/mount-utils v0.23.3 // indirect
 	k8s.io/utils v0.0.0-20220210201930-3a6ce19ff2f9 // indirect
 	sigs.k8s.io/apiserver-network-proxy/konnectivity-client v0.0.30 // indirect
 	sigs.k8s.io/json v0.0.0-20211208200746-9f7c6b3444d2 // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.1 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
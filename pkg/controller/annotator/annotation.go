@@ -0,0 +1,254 @@
+package annotator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+// AnnotationFormat is the format in which node load is stored.
+type AnnotationFormat string
+
+const (
+	// LegacyAnnotationFormat stores each metric in its own annotation as "value,timestamp".
+	LegacyAnnotationFormat AnnotationFormat = "legacy"
+	// StructuredAnnotationFormat stores all metrics in one JSON annotation.
+	StructuredAnnotationFormat AnnotationFormat = "structured"
+
+	// AnnotationFlushPeriod is how often buffered writes of structured annotations
+	// are sent, within which metrics of a node synced in one round are merged.
+	AnnotationFlushPeriod = 10 * time.Second
+)
+
+// loadAnnotator writes metric values of nodes.
+type loadAnnotator interface {
+	// annotate writes the value of metric key to the node.
+	annotate(node *v1.Node, key string, value float64) error
+	// isUpToDate checks if the node already holds the value of metric key,
+	// which was updated within maxAge.
+	isUpToDate(node *v1.Node, key string, value float64, maxAge time.Duration) bool
+	// forget drops the state kept for the node, which is called when it is deleted.
+	forget(nodeName string)
+	// flush sends writes buffered since the last flush, which is called periodically.
+	flush()
+}
+
+// newLoadAnnotator returns the loadAnnotator of the given format.
+func newLoadAnnotator(kubeClient clientset.Interface, format AnnotationFormat, timestampFormat utils.TimestampFormat) loadAnnotator {
+	if format == StructuredAnnotationFormat {
+		return &structuredAnnotator{
+			kubeClient: kubeClient,
+			loads:      make(map[string]*utils.NodeLoad),
+			dirty:      make(map[string]sets.String),
+		}
+	}
+
+	return &legacyAnnotator{kubeClient: kubeClient, timestampFormat: timestampFormat}
+}
+
+// legacyAnnotator patches one annotation per metric.
+type legacyAnnotator struct {
+	kubeClient      clientset.Interface
+	timestampFormat utils.TimestampFormat
+}
+
+func (l *legacyAnnotator) annotate(node *v1.Node, key string, value float64) error {
+	err := patchNodeAnnotation(l.kubeClient, node, key, formatValue(value)+","+utils.FormatTimestamp(time.Now(), l.timestampFormat))
+	metrics.ObservePatch(key, err)
+	return err
+}
+
+func (l *legacyAnnotator) isUpToDate(node *v1.Node, key string, value float64, maxAge time.Duration) bool {
+	annotation, ok := node.Annotations[key]
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(annotation, ",")
+	if len(parts) != 2 {
+		return false
+	}
+
+	lastValue, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || formatValue(lastValue) != formatValue(value) {
+		return false
+	}
+
+	updateTime, err := utils.ParseTimestamp(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return time.Since(updateTime) < maxAge
+}
+
+func (l *legacyAnnotator) forget(nodeName string) {}
+
+func (l *legacyAnnotator) flush() {}
+
+// structuredAnnotator keeps all metrics of a node in one JSON annotation. Writes are
+// buffered in memory and merged into the load last written, so that all metrics of
+// a node synced within one flush period are sent by a single patch, instead of one
+// patch per metric waking every node watcher.
+type structuredAnnotator struct {
+	kubeClient clientset.Interface
+
+	mu    sync.Mutex
+	loads map[string]*utils.NodeLoad
+	// dirty keeps the metrics of each node written since the last flush.
+	dirty map[string]sets.String
+}
+
+func (s *structuredAnnotator) annotate(node *v1.Node, key string, value float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	load := &utils.NodeLoad{Metrics: map[string]utils.MetricSample{}}
+	for name, sample := range s.lastLoad(node).Metrics {
+		load.Metrics[name] = sample
+	}
+	load.Metrics[key] = utils.MetricSample{
+		Value:     value,
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+	}
+	s.loads[node.Name] = load
+
+	if s.dirty[node.Name] == nil {
+		s.dirty[node.Name] = sets.NewString()
+	}
+	s.dirty[node.Name].Insert(key)
+
+	return nil
+}
+
+func (s *structuredAnnotator) isUpToDate(node *v1.Node, key string, value float64, maxAge time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sample, ok := s.lastLoad(node).Metrics[key]
+	if !ok {
+		return false
+	}
+
+	return formatValue(sample.Value) == formatValue(value) && time.Since(sample.Timestamp) < maxAge
+}
+
+func (s *structuredAnnotator) forget(nodeName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.loads, nodeName)
+	delete(s.dirty, nodeName)
+}
+
+// flush patches each node written since the last flush once with all its metrics.
+// Nodes failed to patch are retried in the next flush.
+func (s *structuredAnnotator) flush() {
+	s.mu.Lock()
+	loads, dirty := make(map[string]*utils.NodeLoad, len(s.dirty)), s.dirty
+	for nodeName := range dirty {
+		loads[nodeName] = s.loads[nodeName]
+	}
+	s.dirty = make(map[string]sets.String)
+	s.mu.Unlock()
+
+	for nodeName, load := range loads {
+		err := patchNodeLoad(s.kubeClient, nodeName, load)
+		for _, key := range dirty[nodeName].List() {
+			metrics.ObservePatch(key, err)
+		}
+		if err == nil {
+			continue
+		}
+
+		klog.Warningf("Failed to patch load of node[%s], retry in the next flush: %v", nodeName, err)
+		s.mu.Lock()
+		if _, ok := s.loads[nodeName]; ok {
+			if s.dirty[nodeName] == nil {
+				s.dirty[nodeName] = sets.NewString()
+			}
+			s.dirty[nodeName] = s.dirty[nodeName].Union(dirty[nodeName])
+		}
+		s.mu.Unlock()
+	}
+}
+
+// lastLoad returns the load last written to the node, or the one decoded from
+// node annotation after restart. It must be called with s.mu held.
+func (s *structuredAnnotator) lastLoad(node *v1.Node) *utils.NodeLoad {
+	if load, ok := s.loads[node.Name]; ok {
+		return load
+	}
+
+	if data, ok := node.Annotations[utils.NodeLoadAnnotationKey]; ok {
+		if load, err := utils.ParseNodeLoad(data); err == nil {
+			return load
+		}
+	}
+
+	return &utils.NodeLoad{}
+}
+
+func patchNodeLoad(kubeClient clientset.Interface, nodeName string, load *utils.NodeLoad) error {
+	data, err := utils.FormatNodeLoad(load)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				utils.NodeLoadAnnotationKey: data,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = kubeClient.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func patchNodeAnnotation(kubeClient clientset.Interface, node *v1.Node, key, value string) error {
+	annotation := node.GetAnnotations()
+	if annotation == nil {
+		annotation = map[string]string{}
+	}
+
+	operator := "add"
+	_, exist := annotation[key]
+	if exist {
+		operator = "replace"
+	}
+
+	patchAnnotationTemplate :=
+		`[{
+		"op": "%s",
+		"path": "/metadata/annotations/%s",
+		"value": "%s"
+	}]`
+
+	patchData := fmt.Sprintf(patchAnnotationTemplate, operator, key, value)
+
+	_, err := kubeClient.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.JSONPatchType, []byte(patchData), metav1.PatchOptions{})
+	return err
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', 5, 64)
+}
@@ -0,0 +1,182 @@
+package annotator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func getStructuredLoad(t *testing.T, client *fake.Clientset, nodeName string) *utils.NodeLoad {
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+
+	load, err := utils.ParseNodeLoad(node.Annotations[utils.NodeLoadAnnotationKey])
+	if err != nil {
+		t.Fatalf("failed to decode node load: %v", err)
+	}
+	return load
+}
+
+func TestStructuredAnnotator_Annotate(t *testing.T) {
+	// the node is annotated before restart, along with a legacy annotation.
+	lastUpdated := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	loadData, err := utils.FormatNodeLoad(&utils.NodeLoad{Metrics: map[string]utils.MetricSample{
+		"cpu_usage_avg_5m": {Value: 0.3, Timestamp: lastUpdated},
+	}})
+	if err != nil {
+		t.Fatalf("failed to encode node load: %v", err)
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		Annotations: map[string]string{
+			utils.NodeLoadAnnotationKey: loadData,
+			"mem_usage_avg_5m":          "0.40000,2022-01-01T00:00:00Z",
+		},
+	}}
+
+	client := fake.NewSimpleClientset(node)
+	annotator := newLoadAnnotator(client, StructuredAnnotationFormat, utils.LegacyTimestampFormat)
+
+	if !annotator.isUpToDate(node, "cpu_usage_avg_5m", 0.3, time.Hour) {
+		t.Errorf("value decoded from annotation is not up to date")
+	}
+	if annotator.isUpToDate(node, "cpu_usage_avg_5m", 0.3, time.Second) {
+		t.Errorf("value older than max age is up to date")
+	}
+	if annotator.isUpToDate(node, "cpu_usage_avg_5m", 0.5, time.Hour) {
+		t.Errorf("changed value is up to date")
+	}
+
+	for key, value := range map[string]float64{"mem_usage_avg_5m": 0.5, HotValueKey: 1} {
+		if err := annotator.annotate(node, key, value); err != nil {
+			t.Fatalf("failed to annotate node: %v", err)
+		}
+	}
+	if len(client.Actions()) != 0 {
+		t.Fatalf("got actions %v before flush, want none", client.Actions())
+	}
+
+	// all metrics written since the last flush are sent by one merge patch.
+	annotator.flush()
+
+	var patches []clienttesting.PatchAction
+	for _, action := range client.Actions() {
+		if patch, ok := action.(clienttesting.PatchAction); ok {
+			patches = append(patches, patch)
+		}
+	}
+	if len(patches) != 1 || patches[0].GetPatchType() != types.MergePatchType {
+		t.Fatalf("got patches %v, want one merge patch", patches)
+	}
+
+	load := getStructuredLoad(t, client, "node-1")
+	if sample := load.Metrics["cpu_usage_avg_5m"]; sample.Value != 0.3 || !sample.Timestamp.Equal(lastUpdated) {
+		t.Errorf("got cpu_usage_avg_5m %v, want the sample written before restart", sample)
+	}
+	if sample := load.Metrics["mem_usage_avg_5m"]; sample.Value != 0.5 {
+		t.Errorf("got mem_usage_avg_5m %v, want 0.5", sample.Value)
+	}
+	if sample := load.Metrics[HotValueKey]; sample.Value != 1 {
+		t.Errorf("got %s %v, want 1", HotValueKey, sample.Value)
+	}
+	if !annotator.isUpToDate(node, "mem_usage_avg_5m", 0.5, time.Minute) {
+		t.Errorf("value just written is not up to date")
+	}
+
+	// nothing is sent if nothing is written since the last flush.
+	annotator.flush()
+	if len(client.Actions()) != 2 {
+		t.Errorf("got %d actions, want no more patch", len(client.Actions()))
+	}
+}
+
+func TestStructuredAnnotator_FlushRetry(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	client := fake.NewSimpleClientset(node)
+	annotator := newLoadAnnotator(client, StructuredAnnotationFormat, utils.LegacyTimestampFormat)
+
+	client.PrependReactor("patch", "nodes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("conflict")
+	})
+	if err := annotator.annotate(node, "cpu_usage_avg_5m", 0.3); err != nil {
+		t.Fatalf("failed to annotate node: %v", err)
+	}
+	annotator.flush()
+
+	// the failed node is patched again in the next flush.
+	client.ReactionChain = client.ReactionChain[1:]
+	annotator.flush()
+
+	if load := getStructuredLoad(t, client, "node-1"); load.Metrics["cpu_usage_avg_5m"].Value != 0.3 {
+		t.Errorf("got load %v, want cpu_usage_avg_5m 0.3 patched by retry", load.Metrics)
+	}
+}
+
+func TestStructuredAnnotator_ConcurrentAnnotate(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	client := fake.NewSimpleClientset(node)
+	annotator := newLoadAnnotator(client, StructuredAnnotationFormat, utils.LegacyTimestampFormat)
+
+	// every sync holds the same stale node, so metrics are only kept if updates
+	// of the node are merged.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := annotator.annotate(node, fmt.Sprintf("metric_%d", i), float64(i)/10); err != nil {
+				t.Errorf("failed to annotate node: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	annotator.flush()
+
+	load := getStructuredLoad(t, client, "node-1")
+	if len(load.Metrics) != 10 {
+		t.Errorf("got %d metrics %v, want 10", len(load.Metrics), load.Metrics)
+	}
+}
+
+func TestStructuredAnnotator_Forget(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	client := fake.NewSimpleClientset(node)
+	annotator := newLoadAnnotator(client, StructuredAnnotationFormat, utils.LegacyTimestampFormat).(*structuredAnnotator)
+
+	if err := annotator.annotate(node, "cpu_usage_avg_5m", 0.3); err != nil {
+		t.Fatalf("failed to annotate node: %v", err)
+	}
+	if len(annotator.loads) != 1 || len(annotator.dirty) != 1 {
+		t.Fatalf("got %d loads and %d dirty nodes, want 1", len(annotator.loads), len(annotator.dirty))
+	}
+
+	n := &nodeController{Controller: &Controller{loadAnnotator: annotator}}
+	n.handleDeleteNode(cache.DeletedFinalStateUnknown{Key: "node-1", Obj: node})
+
+	if len(annotator.loads) != 0 || len(annotator.dirty) != 0 {
+		t.Errorf("got %d loads and %d dirty nodes after node deleted, want 0", len(annotator.loads), len(annotator.dirty))
+	}
+
+	// the deleted node is not patched.
+	annotator.flush()
+	if len(client.Actions()) != 0 {
+		t.Errorf("got actions %v, want none for the deleted node", client.Actions())
+	}
+}
@@ -0,0 +1,77 @@
+package annotator
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+const (
+	// MaxUnchangedLoadAge is the max age of a load annotation which is not refreshed
+	// in batch sync if its value is unchanged. It is a minute less than the extra active
+	// period given by the scheduler, so that annotations refreshed in the next period never
+	// expire there, even if the query is slow or clocks are skewed.
+	MaxUnchangedLoadAge = policy.ExtraActivePeriod - time.Minute
+	// MaxUnchangedHotValueAge is the max age of an unchanged hot value annotation.
+	MaxUnchangedHotValueAge = 2 * time.Minute
+)
+
+// syncMetricOfNodes queries the metric of given nodes by one batch query, and
+// patches nodes whose value changed. Nodes missing in the result fall back to
+// per-node sync.
+func (n *nodeController) syncMetricOfNodes(batchProvider provider.BatchMetricsProvider, metricName string, nodes []*v1.Node) {
+	startTime := time.Now()
+	defer func() {
+		klog.Infof("Finished batch syncing metric %q (%v)", metricName, time.Since(startTime))
+	}()
+
+	if len(nodes) == 0 {
+		return
+	}
+
+	queryStartTime := time.Now()
+	values, err := batchProvider.QueryNodesMetric(metricName, nodes)
+	metrics.ObserveQuery(metricName, "batch", queryStartTime, err)
+	if err != nil {
+		klog.Warningf("Failed to batch query metric %s, fall back to per-node sync: %v", metricName, err)
+		for _, node := range nodes {
+			n.queue.Add(handlingMetaKeyWithMetricName(node.Name, metricName))
+		}
+		return
+	}
+	// the metric is synced even if no node is patched because values are unchanged.
+	metrics.LastSyncTimestamp.WithLabelValues(metricName).SetToCurrentTime()
+
+	workqueue.ParallelizeUntil(context.TODO(), n.concurrentSyncs, len(nodes), func(i int) {
+		node := nodes[i]
+
+		value, ok := values[node.Name]
+		if !ok {
+			klog.V(4).Infof("Metric %s of node[%s] is missing in batch result, fall back to per-node sync", metricName, node.Name)
+			n.queue.Add(handlingMetaKeyWithMetricName(node.Name, metricName))
+			return
+		}
+
+		if !n.loadAnnotator.isUpToDate(node, metricName, value, MaxUnchangedLoadAge) {
+			if err := n.loadAnnotator.annotate(node, metricName, value); err != nil {
+				klog.Warningf("Failed to annotate %s of node[%s], fall back to per-node sync: %v", metricName, node.Name, err)
+				n.queue.Add(handlingMetaKeyWithMetricName(node.Name, metricName))
+				return
+			}
+		}
+
+		hotValue := getNodeHotValue(n.bindingRecords, node, n.policy)
+		if !n.loadAnnotator.isUpToDate(node, HotValueKey, hotValue, MaxUnchangedHotValueAge) {
+			if err := n.loadAnnotator.annotate(node, HotValueKey, hotValue); err != nil {
+				klog.Warningf("Failed to annotate hot value of node[%s]: %v", node.Name, err)
+			}
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // AnnotatorConfiguration holds configuration for a node annotator.
 type AnnotatorConfiguration struct {
 	// BindingHeapSize limits the size of Binding Heap, which stores the lastest
@@ -11,4 +13,24 @@ type AnnotatorConfiguration struct {
 	PolicyConfigPath string
 	// PrometheusAddr is the address of Prometheus Service.
 	PrometheusAddr string
+	// MetricsProvider specified the backend which metrics data is pulled from,
+	// one of prometheus, metrics-server and static.
+	MetricsProvider string
+	// MetricsServerPollInterval specified the interval of polling node metrics
+	// from metrics-server, used only by metrics-server provider.
+	MetricsServerPollInterval time.Duration
+	// BatchSync enables querying one metric of all nodes at once if the metrics
+	// provider supports, and only patching nodes whose value changed.
+	BatchSync bool
+	// AnnotationFormat specified the format in which node load is stored, either
+	// legacy, one annotation per metric, or structured, one JSON annotation for all.
+	AnnotationFormat string
+	// TimestampFormat specified the format of timestamps in legacy annotations,
+	// one of rfc3339, unix and legacy.
+	TimestampFormat string
+	// BindingSource specified where bindings of pods to compute hot values come
+	// from, either event for Scheduled events, or pod for spec.nodeName of pods.
+	BindingSource string
+	// StaticMetricsPath specified the path of metrics data file used by static provider.
+	StaticMetricsPath string
 }
@@ -12,9 +12,12 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	annotatorconfig "github.com/gocrane/crane-scheduler/pkg/controller/annotator/config"
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
 	policy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
 
-	prom "github.com/gocrane/crane-scheduler/pkg/controller/prometheus"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
 )
 
 // Controller is Controller for node annotator.
@@ -23,60 +26,102 @@ type Controller struct {
 	nodeInformerSynced cache.InformerSynced
 	nodeLister         corelisters.NodeLister
 
-	eventInformer       coreinformers.EventInformer
-	eventInformerSynced cache.InformerSynced
-	eventLister         corelisters.EventLister
+	// eventInformer and eventLister are only set if bindings come from events,
+	// while podInformer is only set if bindings come from pods.
+	eventInformer coreinformers.EventInformer
+	eventLister   corelisters.EventLister
+	podInformer   coreinformers.PodInformer
+	// bindingInformerSynced checks if the informer of bindings is synced.
+	bindingInformerSynced cache.InformerSynced
 
-	kubeClient clientset.Interface
-	promClient prom.PromClient
+	kubeClient      clientset.Interface
+	metricsProvider provider.MetricsProvider
 
 	policy         policy.DynamicSchedulerPolicy
-	bindingRecords *BindingRecords
+	bindingRecords *utils.BindingRecords
+	loadAnnotator  loadAnnotator
+
+	// concurrentSyncs is the number of workers, which also limits the concurrency of batch sync.
+	concurrentSyncs int
+	// batchSync enables querying one metric of all nodes at once if supported by metrics provider.
+	batchSync bool
 }
 
 // NewController returns a Node Annotator object.
 func NewNodeAnnotator(
 	nodeInformer coreinformers.NodeInformer,
 	eventInformer coreinformers.EventInformer,
+	podInformer coreinformers.PodInformer,
 	kubeClient clientset.Interface,
-	promClient prom.PromClient,
-	policy policy.DynamicSchedulerPolicy,
-	bingdingHeapSize int32,
+	metricsProvider provider.MetricsProvider,
+	dynamicPolicy policy.DynamicSchedulerPolicy,
+	config *annotatorconfig.AnnotatorConfiguration,
 ) *Controller {
-	return &Controller{
-		nodeInformer:        nodeInformer,
-		nodeInformerSynced:  nodeInformer.Informer().HasSynced,
-		nodeLister:          nodeInformer.Lister(),
-		eventInformer:       eventInformer,
-		eventInformerSynced: eventInformer.Informer().HasSynced,
-		eventLister:         eventInformer.Lister(),
-		kubeClient:          kubeClient,
-		promClient:          promClient,
-		policy:              policy,
-		bindingRecords:      NewBindingRecords(bingdingHeapSize, getMaxHotVauleTimeRange(policy.Spec.HotValue)),
+	metrics.Register()
+
+	c := &Controller{
+		nodeInformer:       nodeInformer,
+		nodeInformerSynced: nodeInformer.Informer().HasSynced,
+		nodeLister:         nodeInformer.Lister(),
+		kubeClient:         kubeClient,
+		metricsProvider:    metricsProvider,
+		policy:             dynamicPolicy,
+		bindingRecords:     utils.NewBindingRecords(config.BindingHeapSize, policy.GetMaxHotValueTimeRange(dynamicPolicy.Spec.HotValue)),
+		loadAnnotator:      newLoadAnnotator(kubeClient, AnnotationFormat(config.AnnotationFormat), utils.TimestampFormat(config.TimestampFormat)),
+		concurrentSyncs:    int(config.ConcurrentSyncs),
+		batchSync:          config.BatchSync,
+	}
+
+	// only the informer of the binding source is started by the informer factory.
+	if BindingSource(config.BindingSource) == PodBindingSource {
+		c.podInformer = podInformer
+		c.bindingInformerSynced = podInformer.Informer().HasSynced
+	} else {
+		c.eventInformer = eventInformer
+		c.eventLister = eventInformer.Lister()
+		c.bindingInformerSynced = eventInformer.Informer().HasSynced
 	}
+
+	return c
 }
 
 // Run runs node annotator.
-func (c *Controller) Run(worker int, stopCh <-chan struct{}) error {
+func (c *Controller) Run(stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 
-	eventController := newEventController(c)
-	c.eventInformer.Informer().AddEventHandler(eventController.handles())
+	var eventController *eventController
+	if c.podInformer != nil {
+		c.podInformer.Informer().AddEventHandler(newPodController(c).handles())
+	} else {
+		eventController = newEventController(c)
+		c.eventInformer.Informer().AddEventHandler(eventController.handles())
+	}
 
 	nodeController := newNodeController(c)
+	c.nodeInformer.Informer().AddEventHandler(nodeController.handles())
 
-	if !cache.WaitForCacheSync(stopCh, c.nodeInformerSynced, c.eventInformerSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.nodeInformerSynced, c.bindingInformerSynced) {
 		return fmt.Errorf("failed to wait for cache sync for annotator")
 	}
 	klog.Info("Caches are synced for controller")
 
-	for i := 0; i < worker; i++ {
+	for i := 0; i < c.concurrentSyncs; i++ {
 		go wait.Until(nodeController.Run, time.Second, stopCh)
-		go wait.Until(eventController.Run, time.Second, stopCh)
+		if eventController != nil {
+			go wait.Until(eventController.Run, time.Second, stopCh)
+		}
+	}
+
+	if runnable, ok := c.metricsProvider.(provider.Runnable); ok {
+		go runnable.Run(stopCh)
 	}
 
-	go wait.Until(c.bindingRecords.BindingsGC, time.Minute, stopCh)
+	go wait.Until(c.loadAnnotator.flush, AnnotationFlushPeriod, stopCh)
+
+	go wait.Until(func() {
+		c.bindingRecords.BindingsGC()
+		metrics.BindingRecords.Set(float64(c.bindingRecords.Len()))
+	}, time.Minute, stopCh)
 
 	nodeController.CreateMetricSyncTicker(stopCh)
 
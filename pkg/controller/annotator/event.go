@@ -9,6 +9,9 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
 type eventController struct {
@@ -111,11 +114,12 @@ func (e *eventController) reconcile(key string) error {
 	}
 
 	e.bindingRecords.AddBinding(binding)
+	metrics.BindingRecords.Set(float64(e.bindingRecords.Len()))
 
 	return nil
 }
 
-func translateEventToBinding(event *v1.Event) (*Binding, error) {
+func translateEventToBinding(event *v1.Event) (*utils.Binding, error) {
 	var metaKey, nodeName string
 
 	_, err := fmt.Fscanf(strings.NewReader(event.Message), "Successfully assigned %s to %s", &metaKey, &nodeName)
@@ -136,7 +140,7 @@ func translateEventToBinding(event *v1.Event) (*Binding, error) {
 		lasteOccuredTime = event.LastTimestamp.Unix()
 	}
 
-	return &Binding{
+	return &utils.Binding{
 		Node:      nodeName,
 		Namespace: namespace,
 		PodName:   name,
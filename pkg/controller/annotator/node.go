@@ -1,22 +1,19 @@
 package annotator
 
 import (
-	"context"
 	"fmt"
-	"strconv"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/types"
-	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	policy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
 
-	prom "github.com/gocrane/crane-scheduler/pkg/controller/prometheus"
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
 	utils "github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
@@ -28,7 +25,8 @@ const (
 
 type nodeController struct {
 	*Controller
-	queue workqueue.RateLimitingInterface
+	queue     workqueue.RateLimitingInterface
+	nodePools *policy.NodePoolSelector
 }
 
 func newNodeController(c *Controller) *nodeController {
@@ -38,9 +36,31 @@ func newNodeController(c *Controller) *nodeController {
 	return &nodeController{
 		Controller: c,
 		queue:      workqueue.NewNamedRateLimitingQueue(nodeRateLimiter, "node_event_queue"),
+		nodePools:  policy.NewNodePoolSelector(c.policy.Spec.NodePools),
 	}
 }
 
+func (n *nodeController) handles() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		DeleteFunc: n.handleDeleteNode,
+	}
+}
+
+// handleDeleteNode drops the state kept by the load annotator for the deleted node.
+func (n *nodeController) handleDeleteNode(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		return
+	}
+
+	klog.V(5).Infof("Node[%s] is deleted", node.Name)
+	n.loadAnnotator.forget(node.Name)
+}
+
 func (n *nodeController) Run() {
 	defer n.queue.ShutDown()
 	klog.Infof("Start to reconcile node events")
@@ -85,12 +105,12 @@ func (n *nodeController) syncNode(key string) (bool, error) {
 		return true, fmt.Errorf("can not find node[%s]: %v", node, err)
 	}
 
-	err = annotateNodeLoad(n.promClient, n.kubeClient, node, metricName)
+	err = annotateNodeLoad(n.metricsProvider, n.loadAnnotator, node, metricName)
 	if err != nil {
 		return false, fmt.Errorf("can not annotate node[%s]: %v", node.Name, err)
 	}
 
-	err = annotateNodeHotValue(n.kubeClient, n.bindingRecords, node, n.policy)
+	err = annotateNodeHotValue(n.loadAnnotator, n.bindingRecords, node, n.policy)
 	if err != nil {
 		return false, err
 	}
@@ -98,61 +118,49 @@ func (n *nodeController) syncNode(key string) (bool, error) {
 	return true, nil
 }
 
-func annotateNodeLoad(promClient prom.PromClient, kubeClient clientset.Interface, node *v1.Node, key string) error {
-	value, err := promClient.QueryByNodeIP(key, getNodeInternalIP(node))
-	if err == nil && len(value) > 0 {
-		return patchNodeAnnotation(kubeClient, node, key, value)
-	}
-	value, err = promClient.QueryByNodeName(key, getNodeName(node))
-	if err == nil && len(value) > 0 {
-		return patchNodeAnnotation(kubeClient, node, key, value)
+func annotateNodeLoad(metricsProvider provider.MetricsProvider, annotator loadAnnotator, node *v1.Node, key string) error {
+	startTime := time.Now()
+	value, err := metricsProvider.QueryNodeMetric(key, node)
+	metrics.ObserveQuery(key, "node", startTime, err)
+	if err != nil {
+		return fmt.Errorf("failed to get data %s{%s}: %v", key, node.Name, err)
 	}
-	return fmt.Errorf("failed to get data %s{%s=%s}: %v", key, node.Name, value, err)
+	return annotator.annotate(node, key, value)
 }
 
-func annotateNodeHotValue(kubeClient clientset.Interface, br *BindingRecords, node *v1.Node, policy policy.DynamicSchedulerPolicy) error {
-	var value int
-
-	for _, p := range policy.Spec.HotValue {
-		value += br.GetLastNodeBindingCount(node.Name, p.TimeRange.Duration) / p.Count
-	}
-
-	return patchNodeAnnotation(kubeClient, node, HotValueKey, strconv.Itoa(value))
+func annotateNodeHotValue(annotator loadAnnotator, br *utils.BindingRecords, node *v1.Node, dynamicPolicy policy.DynamicSchedulerPolicy) error {
+	return annotator.annotate(node, HotValueKey, getNodeHotValue(br, node, dynamicPolicy))
 }
 
-func patchNodeAnnotation(kubeClient clientset.Interface, node *v1.Node, key, value string) error {
-	annotation := node.GetAnnotations()
-	if annotation == nil {
-		annotation = map[string]string{}
-	}
-
-	operator := "add"
-	_, exist := annotation[key]
-	if exist {
-		operator = "replace"
-	}
-
-	patchAnnotationTemplate :=
-		`[{
-		"op": "%s",
-		"path": "/metadata/annotations/%s",
-		"value": "%s"
-	}]`
+func getNodeHotValue(br *utils.BindingRecords, node *v1.Node, dynamicPolicy policy.DynamicSchedulerPolicy) float64 {
+	hotValues := dynamicPolicy.Spec.HotValue
+	ages := br.GetLastNodeBindingAges(node.Name, policy.GetMaxHotValueTimeRange(hotValues))
 
-	patchData := fmt.Sprintf(patchAnnotationTemplate, operator, key, value+","+utils.GetLocalTime())
-
-	_, err := kubeClient.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.JSONPatchType, []byte(patchData), metav1.PatchOptions{})
-	return err
+	return policy.GetHotValue(hotValues, ages)
 }
 
 func (n *nodeController) CreateMetricSyncTicker(stopCh <-chan struct{}) {
 
-	for _, p := range n.policy.Spec.SyncPeriod {
+	batchProvider, batchSync := n.metricsProvider.(provider.BatchMetricsProvider)
+	batchSync = batchSync && n.batchSync
+
+	for _, p := range policy.GetAllSyncPolicies(&n.policy.Spec) {
+		batchSyncMetric := batchSync && batchProvider.SupportsBatchQuery(p.Name)
+		if batchSync && !batchSyncMetric {
+			klog.Infof("Batch query is not supported by metric %s, sync it node by node", p.Name)
+		}
+
 		enqueueFunc := func(policy policy.SyncPolicy) {
 			nodes, err := n.nodeLister.List(labels.Everything())
 			if err != nil {
 				panic(fmt.Errorf("failed to list nodes: %v", err))
 			}
+			nodes = n.filterNodesToSync(nodes, policy)
+
+			if batchSyncMetric {
+				n.syncMetricOfNodes(batchProvider, policy.Name, nodes)
+				return
+			}
 
 			for _, node := range nodes {
 				n.queue.Add(handlingMetaKeyWithMetricName(node.Name, policy.Name))
@@ -176,16 +184,23 @@ func (n *nodeController) CreateMetricSyncTicker(stopCh <-chan struct{}) {
 	}
 }
 
-func getNodeInternalIP(node *v1.Node) string {
-	for _, addr := range node.Status.Addresses {
-		if addr.Type == v1.NodeInternalIP {
-			return addr.Address
-		}
+// filterNodesToSync returns nodes which sync the metric with the same period, taking
+// sync policies of the node pool in place of the global ones.
+func (n *nodeController) filterNodesToSync(nodes []*v1.Node, syncPolicy policy.SyncPolicy) []*v1.Node {
+	if len(n.policy.Spec.NodePools) == 0 {
+		return nodes
 	}
 
-	return node.Name
-}
+	var filtered []*v1.Node
+	for _, node := range nodes {
+		spec := policy.ApplyNodePool(n.policy.Spec, n.nodePools.Select(node.Labels))
+		for _, p := range spec.SyncPeriod {
+			if p.Name == syncPolicy.Name && p.Period == syncPolicy.Period {
+				filtered = append(filtered, node)
+				break
+			}
+		}
+	}
 
-func getNodeName(node *v1.Node) string {
-	return node.Name
+	return filtered
 }
@@ -0,0 +1,190 @@
+package annotator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/component-base/metrics/testutil"
+
+	annotatorconfig "github.com/gocrane/crane-scheduler/pkg/controller/annotator/config"
+	controllermetrics "github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestNodeController_SyncNode(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: map[string]string{"node.alpha.kubernetes.io/ttl": "0"}}}
+
+	tests := []struct {
+		name       string
+		key        string
+		metrics    provider.StaticMetrics
+		wantForget bool
+		wantErr    bool
+		wantValue  string
+	}{
+		{
+			name:       "annotate node load",
+			key:        "node-1/cpu_usage_avg_5m",
+			metrics:    provider.StaticMetrics{"node-1": {"cpu_usage_avg_5m": 0.3}},
+			wantForget: true,
+			wantValue:  "0.30000",
+		},
+		{
+			name:       "metric not found",
+			key:        "node-1/cpu_usage_avg_5m",
+			metrics:    provider.StaticMetrics{},
+			wantForget: false,
+			wantErr:    true,
+		},
+		{
+			name:       "node not found",
+			key:        "node-2/cpu_usage_avg_5m",
+			metrics:    provider.StaticMetrics{},
+			wantForget: true,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid key",
+			key:        "cpu_usage_avg_5m",
+			metrics:    provider.StaticMetrics{},
+			wantForget: true,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(node.DeepCopy())
+			informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+			nodeInformer := informerFactory.Core().V1().Nodes()
+			if err := nodeInformer.Informer().GetStore().Add(node.DeepCopy()); err != nil {
+				t.Fatalf("failed to add node to store: %v", err)
+			}
+
+			p := policy.DynamicSchedulerPolicy{
+				Spec: policy.PolicySpec{
+					HotValue: []policy.HotValuePolicy{{TimeRange: metav1.Duration{Duration: time.Minute}, Count: 2}},
+				},
+			}
+			c := NewNodeAnnotator(nodeInformer, informerFactory.Core().V1().Events(), informerFactory.Core().V1().Pods(), kubeClient,
+				provider.NewStaticProvider(tt.metrics), p, &annotatorconfig.AnnotatorConfiguration{BindingHeapSize: 10, ConcurrentSyncs: 1})
+
+			forget, err := newNodeController(c).syncNode(tt.key)
+			if forget != tt.wantForget {
+				t.Errorf("forget is %v, want %v", forget, tt.wantForget)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error is %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantValue == "" {
+				return
+			}
+
+			got, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get node: %v", err)
+			}
+			value := strings.Split(got.Annotations["cpu_usage_avg_5m"], ",")[0]
+			if value != tt.wantValue {
+				t.Errorf("annotation value is %q, want %q", value, tt.wantValue)
+			}
+			if _, ok := got.Annotations[HotValueKey]; !ok {
+				t.Errorf("hot value annotation not found")
+			}
+		})
+	}
+}
+
+func TestNodeController_SyncMetricOfAllNodes(t *testing.T) {
+	metricName := "cpu_usage_avg_5m"
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "changed", Annotations: map[string]string{
+			metricName: "0.20000," + utils.GetLocalTime(),
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unchanged", Annotations: map[string]string{
+			metricName:  "0.50000," + utils.GetLocalTime(),
+			HotValueKey: "0," + utils.GetLocalTime(),
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "missing", Annotations: map[string]string{}}},
+	}
+	metrics := provider.StaticMetrics{
+		"changed":   {metricName: 0.3},
+		"unchanged": {metricName: 0.5},
+	}
+
+	var objects []runtime.Object
+	for _, node := range nodes {
+		objects = append(objects, node.DeepCopy())
+	}
+	kubeClient := fake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	for _, node := range nodes {
+		if err := nodeInformer.Informer().GetStore().Add(node.DeepCopy()); err != nil {
+			t.Fatalf("failed to add node to store: %v", err)
+		}
+	}
+
+	staticProvider := provider.NewStaticProvider(metrics)
+	c := NewNodeAnnotator(nodeInformer, informerFactory.Core().V1().Events(), informerFactory.Core().V1().Pods(), kubeClient, staticProvider,
+		policy.DynamicSchedulerPolicy{}, &annotatorconfig.AnnotatorConfiguration{BindingHeapSize: 10, ConcurrentSyncs: 2, BatchSync: true})
+	nc := newNodeController(c)
+
+	nc.syncMetricOfNodes(staticProvider, metricName, nodes)
+
+	patched := map[string]bool{}
+	for _, action := range kubeClient.Actions() {
+		if patchAction, ok := action.(clienttesting.PatchAction); ok {
+			patched[patchAction.GetName()] = true
+		}
+	}
+	if !patched["changed"] || patched["unchanged"] || patched["missing"] {
+		t.Errorf("unexpected patched nodes: %v", patched)
+	}
+
+	if nc.queue.Len() != 1 {
+		t.Fatalf("queue length is %d, want 1", nc.queue.Len())
+	}
+	if key, _ := nc.queue.Get(); key != "missing/"+metricName {
+		t.Errorf("fall back key is %v, want %s", key, "missing/"+metricName)
+	}
+}
+
+func TestNodeController_SyncUnchangedMetricOfNodes(t *testing.T) {
+	metricName := "mem_usage_avg_5m"
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unchanged", Annotations: map[string]string{
+		metricName:  "0.50000," + utils.GetLocalTime(),
+		HotValueKey: "0," + utils.GetLocalTime(),
+	}}}
+
+	kubeClient := fake.NewSimpleClientset(node.DeepCopy())
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	staticProvider := provider.NewStaticProvider(provider.StaticMetrics{"unchanged": {metricName: 0.5}})
+	c := NewNodeAnnotator(informerFactory.Core().V1().Nodes(), informerFactory.Core().V1().Events(), informerFactory.Core().V1().Pods(), kubeClient, staticProvider,
+		policy.DynamicSchedulerPolicy{}, &annotatorconfig.AnnotatorConfiguration{BindingHeapSize: 10, ConcurrentSyncs: 1, BatchSync: true})
+
+	startTime := time.Now().Truncate(time.Second)
+	newNodeController(c).syncMetricOfNodes(staticProvider, metricName, []*v1.Node{node})
+
+	if len(kubeClient.Actions()) != 0 {
+		t.Errorf("got actions %v, want no patch of unchanged node", kubeClient.Actions())
+	}
+
+	lastSync, err := testutil.GetGaugeMetricValue(controllermetrics.LastSyncTimestamp.WithLabelValues(metricName))
+	if err != nil {
+		t.Fatalf("failed to get last sync timestamp: %v", err)
+	}
+	if lastSync < float64(startTime.Unix()) {
+		t.Errorf("got last sync timestamp %v, want no earlier than %v", lastSync, startTime.Unix())
+	}
+}
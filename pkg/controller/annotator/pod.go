@@ -0,0 +1,99 @@
+package annotator
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+// BindingSource is where bindings of pods to compute hot values come from.
+type BindingSource string
+
+const (
+	// EventBindingSource parses bindings from messages of Scheduled events.
+	EventBindingSource BindingSource = "event"
+	// PodBindingSource records bindings when spec.nodeName of pods is set.
+	PodBindingSource BindingSource = "pod"
+)
+
+// podController records bindings by watching pods, which does not depend on the
+// message format of Scheduled events, nor is affected by event aggregation.
+type podController struct {
+	*Controller
+}
+
+func newPodController(c *Controller) *podController {
+	return &podController{Controller: c}
+}
+
+func (p *podController) handles() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    p.handleAddPod,
+		UpdateFunc: p.handleUpdatePod,
+	}
+}
+
+// handleAddPod records bindings of pods already scheduled, such as those listed at
+// startup, as long as they are still within the time range of hot values.
+func (p *podController) handleAddPod(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return
+	}
+
+	binding := translatePodToBinding(pod)
+	if time.Since(time.Unix(binding.Timestamp, 0)) >= p.bindingRecords.GetGCTimeRange() {
+		return
+	}
+
+	p.addBinding(binding)
+}
+
+// handleUpdatePod records the binding when spec.nodeName of the pod is set.
+func (p *podController) handleUpdatePod(old, new interface{}) {
+	oldPod, ok := old.(*v1.Pod)
+	if !ok {
+		return
+	}
+	curPod, ok := new.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	if oldPod.Spec.NodeName != "" || curPod.Spec.NodeName == "" {
+		return
+	}
+
+	p.addBinding(translatePodToBinding(curPod))
+}
+
+func (p *podController) addBinding(binding *utils.Binding) {
+	klog.V(5).Infof("Pod %s/%s is bound to node[%s]", binding.Namespace, binding.PodName, binding.Node)
+
+	p.bindingRecords.AddBinding(binding)
+	metrics.BindingRecords.Set(float64(p.bindingRecords.Len()))
+}
+
+// translatePodToBinding returns the binding of a scheduled pod. The binding time is
+// the last transition time of the PodScheduled condition, or now if not found.
+func translatePodToBinding(pod *v1.Pod) *utils.Binding {
+	timestamp := time.Now().UTC().Unix()
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			timestamp = condition.LastTransitionTime.Unix()
+			break
+		}
+	}
+
+	return &utils.Binding{
+		Node:      pod.Spec.NodeName,
+		Namespace: pod.Namespace,
+		PodName:   pod.Name,
+		Timestamp: timestamp,
+	}
+}
@@ -0,0 +1,48 @@
+package annotator
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	utils "github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestPodController_RecordBindings(t *testing.T) {
+	p := newPodController(&Controller{bindingRecords: utils.NewBindingRecords(10, 5*time.Minute)})
+
+	scheduledAt := time.Now().Add(-time.Minute)
+	newPod := func(name, nodeName string, scheduledAt time.Time) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+		}
+		if nodeName != "" {
+			pod.Status.Conditions = []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduledAt)},
+			}
+		}
+		return pod
+	}
+
+	// pending pods and pods scheduled long ago are not recorded.
+	p.handleAddPod(newPod("pending", "", time.Time{}))
+	p.handleAddPod(newPod("old", "node-1", time.Now().Add(-time.Hour)))
+	p.handleAddPod(newPod("recent", "node-1", scheduledAt))
+
+	p.handleUpdatePod(newPod("bound", "", time.Time{}), newPod("bound", "node-1", scheduledAt))
+	// updates of pods already bound are not recorded again.
+	p.handleUpdatePod(newPod("bound", "node-1", scheduledAt), newPod("bound", "node-1", scheduledAt))
+
+	ages := p.bindingRecords.GetLastNodeBindingAges("node-1", 5*time.Minute)
+	if len(ages) != 2 {
+		t.Fatalf("got %d bindings, want 2", len(ages))
+	}
+	for _, age := range ages {
+		if age < 59*time.Second || age > 61*time.Second {
+			t.Errorf("got binding age %v, want about 1m", age)
+		}
+	}
+}
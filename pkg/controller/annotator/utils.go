@@ -3,9 +3,6 @@ package annotator
 import (
 	"fmt"
 	"strings"
-	"time"
-
-	policy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
 )
 
 func splitMetaKeyWithMetricName(key string) (string, string, error) {
@@ -21,19 +18,3 @@ func splitMetaKeyWithMetricName(key string) (string, string, error) {
 func handlingMetaKeyWithMetricName(nodeName, metricName string) string {
 	return nodeName + "/" + metricName
 }
-
-func getMaxHotVauleTimeRange(hotValues []policy.HotValuePolicy) time.Duration {
-	var max time.Duration
-
-	if hotValues == nil {
-		return max
-	}
-
-	for _, tr := range hotValues {
-		if max < tr.TimeRange.Duration {
-			max = tr.TimeRange.Duration
-		}
-	}
-
-	return max
-}
@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// DeschedulerConfiguration holds configuration for a load-aware descheduler.
+type DeschedulerConfiguration struct {
+	// Enabled specified whether pods are evicted from overloaded nodes.
+	Enabled bool
+	// Interval specified how often the load of nodes is checked.
+	Interval time.Duration
+	// HighWatermark specified the ratio to thresholds of predicates, above which
+	// the usage of a node is regarded as overloaded.
+	HighWatermark float64
+	// LowWatermark specified the ratio to thresholds of predicates, below which
+	// the usage of a node is regarded as underloaded and can take evicted pods.
+	LowWatermark float64
+	// SustainedPeriod specified how long a node must stay overloaded before pods
+	// are evicted from it.
+	SustainedPeriod time.Duration
+	// MaxEvictionsPerNode limits the number of pods evicted from one node each time.
+	MaxEvictionsPerNode int32
+}
@@ -0,0 +1,303 @@
+package descheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	deschedulerconfig "github.com/gocrane/crane-scheduler/pkg/controller/descheduler/config"
+	"github.com/gocrane/crane-scheduler/pkg/controller/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+const (
+	// EvictableLabelKey is the pod label to opt in eviction by the descheduler,
+	// whose value must be "true".
+	EvictableLabelKey = "descheduler.crane.io/evictable"
+
+	// systemCriticalPriority is the priority of system-cluster-critical pods, and
+	// the least one of critical pods.
+	systemCriticalPriority = 2000000000
+)
+
+// Descheduler evicts pods from nodes overloaded for a sustained period, so that
+// they are rescheduled by the Dynamic plugin to nodes with lower load.
+type Descheduler struct {
+	nodeInformerSynced cache.InformerSynced
+	nodeLister         corelisters.NodeLister
+	podInformerSynced  cache.InformerSynced
+	podLister          corelisters.PodLister
+
+	kubeClient clientset.Interface
+	recorder   record.EventRecorder
+
+	policy policy.DynamicSchedulerPolicy
+	config *deschedulerconfig.DeschedulerConfiguration
+
+	// overloadedSince is when each node is found overloaded, which is reset after
+	// pods are evicted from the node, to wait for the effect of the evictions.
+	overloadedSince map[string]time.Time
+}
+
+// NewDescheduler returns a Descheduler object.
+func NewDescheduler(
+	nodeInformer coreinformers.NodeInformer,
+	podInformer coreinformers.PodInformer,
+	kubeClient clientset.Interface,
+	recorder record.EventRecorder,
+	dynamicPolicy policy.DynamicSchedulerPolicy,
+	config *deschedulerconfig.DeschedulerConfiguration,
+) *Descheduler {
+	metrics.Register()
+
+	return &Descheduler{
+		nodeInformerSynced: nodeInformer.Informer().HasSynced,
+		nodeLister:         nodeInformer.Lister(),
+		podInformerSynced:  podInformer.Informer().HasSynced,
+		podLister:          podInformer.Lister(),
+		kubeClient:         kubeClient,
+		recorder:           recorder,
+		policy:             dynamicPolicy,
+		config:             config,
+		overloadedSince:    make(map[string]time.Time),
+	}
+}
+
+// Run runs the descheduler.
+func (d *Descheduler) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+
+	if !cache.WaitForCacheSync(stopCh, d.nodeInformerSynced, d.podInformerSynced) {
+		return fmt.Errorf("failed to wait for cache sync for descheduler")
+	}
+	klog.Info("Caches are synced for descheduler")
+
+	go wait.Until(d.deschedule, d.config.Interval, stopCh)
+
+	<-stopCh
+	return nil
+}
+
+// overloadedNode is a node overloaded for the sustained period, with the max ratio
+// of its usage to thresholds.
+type overloadedNode struct {
+	name  string
+	ratio float64
+}
+
+// deschedule evicts pods from nodes overloaded for the sustained period, as long as
+// there are nodes underloaded to take them.
+func (d *Descheduler) deschedule() {
+	nodes, err := d.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list nodes: %v", err)
+		return
+	}
+
+	now, overloadedSince := time.Now(), make(map[string]time.Time)
+
+	var overloaded []overloadedNode
+	var underloaded int
+	for _, node := range nodes {
+		ratio, ok := getLoadRatio(node, d.policy.Spec)
+		if !ok {
+			continue
+		}
+
+		if ratio < d.config.LowWatermark && isSchedulable(node) {
+			underloaded++
+		}
+
+		if ratio <= d.config.HighWatermark {
+			continue
+		}
+
+		since, found := d.overloadedSince[node.Name]
+		if !found {
+			since = now
+		}
+		overloadedSince[node.Name] = since
+
+		if now.Sub(since) >= d.config.SustainedPeriod {
+			overloaded = append(overloaded, overloadedNode{name: node.Name, ratio: ratio})
+		}
+	}
+	d.overloadedSince = overloadedSince
+
+	metrics.OverloadedNodes.Set(float64(len(overloaded)))
+	if len(overloaded) == 0 {
+		return
+	}
+	if underloaded == 0 {
+		klog.Warningf("%d nodes are overloaded, but no node is below the low watermark to take evicted pods", len(overloaded))
+		return
+	}
+
+	pods, err := d.podLister.List(labels.SelectorFromSet(labels.Set{EvictableLabelKey: "true"}))
+	if err != nil {
+		klog.Errorf("Failed to list evictable pods: %v", err)
+		return
+	}
+
+	podsByNode := make(map[string][]*v1.Pod)
+	for _, pod := range pods {
+		if isEvictable(pod) {
+			podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+		}
+	}
+
+	// the most overloaded nodes go first, and each underloaded node takes one pod at most.
+	sort.Slice(overloaded, func(i, j int) bool {
+		return overloaded[i].ratio > overloaded[j].ratio
+	})
+
+	budget := underloaded
+	for _, node := range overloaded {
+		if budget == 0 {
+			break
+		}
+
+		candidates := podsByNode[node.name]
+		sortByEvictionOrder(candidates)
+
+		var evicted int32
+		for _, pod := range candidates {
+			if evicted >= d.config.MaxEvictionsPerNode || budget == 0 {
+				break
+			}
+			if err := d.evict(pod, node); err != nil {
+				continue
+			}
+			evicted++
+			budget--
+		}
+
+		if evicted > 0 {
+			delete(d.overloadedSince, node.name)
+		}
+	}
+}
+
+func (d *Descheduler) evict(pod *v1.Pod, node overloadedNode) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+
+	// the eviction is refused by the API server if it violates any PodDisruptionBudget.
+	err := d.kubeClient.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), eviction)
+	if err != nil {
+		result := "failed"
+		if apierrors.IsTooManyRequests(err) {
+			result = "blocked"
+		}
+		metrics.Evictions.WithLabelValues(result).Inc()
+		klog.Warningf("Failed to evict pod %s/%s from node[%s]: %v", pod.Namespace, pod.Name, node.name, err)
+		return err
+	}
+
+	metrics.Evictions.WithLabelValues("evicted").Inc()
+	klog.Infof("Evicted pod %s/%s from node[%s] with load at %.2f of thresholds", pod.Namespace, pod.Name, node.name, node.ratio)
+	if d.recorder != nil {
+		d.recorder.Eventf(pod, v1.EventTypeNormal, "Descheduled", "Evicted from node %s with load at %.2f of thresholds", node.name, node.ratio)
+	}
+
+	return nil
+}
+
+// getLoadRatio returns the max ratio of the usage of the node to thresholds of
+// predicates of its node pool, and false if the usage of any predicate is not available.
+// Like the scheduler, predicates of metrics not synced are skipped.
+func getLoadRatio(node *v1.Node, spec policy.PolicySpec) (float64, bool) {
+	var ratio float64
+
+	spec = policy.ApplyNodePool(spec, policy.NewNodePoolSelector(spec.NodePools).Select(node.Labels))
+	for _, predicatePolicy := range spec.Predicate {
+		if predicatePolicy.MaxLimitPecent == 0 {
+			continue
+		}
+
+		activeDuration, err := policy.GetActiveDuration(spec.SyncPeriod, predicatePolicy.Name)
+		if err != nil || activeDuration == 0 {
+			continue
+		}
+
+		usage, err := utils.GetNodeUsage(node.Annotations, predicatePolicy.Name, activeDuration)
+		if err != nil {
+			return 0, false
+		}
+		ratio = math.Max(ratio, usage/predicatePolicy.MaxLimitPecent)
+	}
+
+	return ratio, true
+}
+
+// isSchedulable checks if the node is able to take evicted pods, that is, it is
+// neither cordoned nor tainted to repel pods without tolerations.
+func isSchedulable(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isEvictable checks if the pod is running and owned by a controller which recreates
+// it, and is neither a daemonset pod, a mirror pod nor a critical pod.
+func isEvictable(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+
+	if metav1.GetControllerOf(pod) == nil || utils.IsDaemonsetPod(pod) {
+		return false
+	}
+
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+
+	return getPodPriority(pod) < systemCriticalPriority
+}
+
+// sortByEvictionOrder sorts pods by priority in ascending order, and then by
+// creation time in descending order, so that the youngest pod of the lowest
+// priority is evicted first.
+func sortByEvictionOrder(pods []*v1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pi, pj := getPodPriority(pods[i]), getPodPriority(pods[j]); pi != pj {
+			return pi < pj
+		}
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+}
+
+func getPodPriority(pod *v1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+
+	return *pod.Spec.Priority
+}
@@ -0,0 +1,126 @@
+package descheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	deschedulerconfig "github.com/gocrane/crane-scheduler/pkg/controller/descheduler/config"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestDeschedule(t *testing.T) {
+	timestamp := utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)
+
+	newNode := func(name, usage string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{"cpu_usage_avg_5m": usage + "," + timestamp},
+		}}
+	}
+
+	isController := true
+	newPod := func(name, nodeName string, priority int32, evictable bool) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &isController}},
+			},
+			Spec:   v1.PodSpec{NodeName: nodeName, Priority: &priority},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if evictable {
+			pod.Labels = map[string]string{EvictableLabelKey: "true"}
+		}
+		return pod
+	}
+
+	pods := []*v1.Pod{
+		newPod("not-opted-in", "hot", 0, false),
+		newPod("high-priority", "hot", 1000, true),
+		newPod("low-priority", "hot", 0, true),
+		newPod("on-warm", "warm", 0, true),
+	}
+
+	client := fake.NewSimpleClientset(pods[0], pods[1], pods[2], pods[3])
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	nodeInformer, podInformer := informerFactory.Core().V1().Nodes(), informerFactory.Core().V1().Pods()
+
+	// hot is above the high watermark, warm is between watermarks, and cold is
+	// below the low watermark.
+	for _, node := range []*v1.Node{newNode("hot", "0.90000"), newNode("warm", "0.60000"), newNode("cold", "0.20000")} {
+		nodeInformer.Informer().GetIndexer().Add(node)
+	}
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	dynamicPolicy := policy.DynamicSchedulerPolicy{Spec: policy.PolicySpec{
+		SyncPeriod: []policy.SyncPolicy{{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}}},
+		Predicate:  []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.8}},
+	}}
+
+	d := NewDescheduler(nodeInformer, podInformer, client, nil, dynamicPolicy, &deschedulerconfig.DeschedulerConfiguration{
+		HighWatermark:       1,
+		LowWatermark:        0.5,
+		SustainedPeriod:     time.Hour,
+		MaxEvictionsPerNode: 1,
+	})
+
+	// the hot node is not overloaded for the sustained period yet.
+	d.deschedule()
+	if evicted := getEvictedPods(client); len(evicted) != 0 {
+		t.Fatalf("got evicted pods %v before the sustained period, want none", evicted)
+	}
+
+	d.overloadedSince["hot"] = time.Now().Add(-2 * time.Hour)
+
+	// the cold node is cordoned or tainted, so that no node is able to take evicted pods.
+	for _, mutate := range []func(node *v1.Node){
+		func(node *v1.Node) { node.Spec.Unschedulable = true },
+		func(node *v1.Node) {
+			node.Spec.Taints = []v1.Taint{{Key: "maintenance", Effect: v1.TaintEffectNoSchedule}}
+		},
+	} {
+		node := newNode("cold", "0.20000")
+		mutate(node)
+		nodeInformer.Informer().GetIndexer().Update(node)
+
+		d.deschedule()
+		if evicted := getEvictedPods(client); len(evicted) != 0 {
+			t.Fatalf("got evicted pods %v without schedulable underloaded nodes, want none", evicted)
+		}
+	}
+
+	nodeInformer.Informer().GetIndexer().Update(newNode("cold", "0.20000"))
+	d.deschedule()
+
+	evicted := getEvictedPods(client)
+	if len(evicted) != 1 || evicted[0] != "default/low-priority" {
+		t.Errorf("got evicted pods %v, want [default/low-priority]", evicted)
+	}
+	if _, ok := d.overloadedSince["hot"]; ok {
+		t.Errorf("overloaded time of the hot node is not reset after evictions")
+	}
+}
+
+func getEvictedPods(client *fake.Clientset) []string {
+	var evicted []string
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" && action.GetSubresource() == "eviction" {
+			object := action.(core.CreateAction).GetObject().(metav1.Object)
+			evicted = append(evicted, fmt.Sprintf("%s/%s", object.GetNamespace(), object.GetName()))
+		}
+	}
+
+	return evicted
+}
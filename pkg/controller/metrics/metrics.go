@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	// register workqueue metrics, such as the depth of node_event_queue and EVENT_event_queue.
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
+)
+
+const (
+	// AnnotatorSubsystem is the subsystem of metrics exposed by the node annotator.
+	AnnotatorSubsystem = "crane_annotator"
+	// DeschedulerSubsystem is the subsystem of metrics exposed by the descheduler.
+	DeschedulerSubsystem = "crane_descheduler"
+)
+
+var (
+	// QueryDuration is the latency of querying metrics from the metrics provider.
+	QueryDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      AnnotatorSubsystem,
+			Name:           "query_duration_seconds",
+			Help:           "Latency of querying metrics from the metrics provider, by metric and query mode.",
+			Buckets:        metrics.ExponentialBuckets(0.005, 2, 12),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"metric", "mode"})
+
+	// QueryErrors counts failed queries to the metrics provider.
+	QueryErrors = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      AnnotatorSubsystem,
+			Name:           "query_errors_total",
+			Help:           "Number of failed queries to the metrics provider, by metric and query mode.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"metric", "mode"})
+
+	// PatchFailures counts failed patches of node annotations.
+	PatchFailures = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      AnnotatorSubsystem,
+			Name:           "patch_failures_total",
+			Help:           "Number of failed patches of node annotations, by metric.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"metric"})
+
+	// LastSyncTimestamp is the last time when a metric is annotated or batch queried successfully.
+	LastSyncTimestamp = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      AnnotatorSubsystem,
+			Name:           "last_sync_timestamp_seconds",
+			Help:           "Unix time when the metric is annotated to any node or batch queried successfully for the last time.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"metric"})
+
+	// BindingRecords is the number of binding records kept to compute hot values.
+	BindingRecords = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      AnnotatorSubsystem,
+			Name:           "binding_records",
+			Help:           "Number of binding records in the heap used to compute hot values.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// OverloadedNodes is the number of nodes overloaded for at least the sustained period.
+	OverloadedNodes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "overloaded_nodes",
+			Help:           "Number of nodes whose usage stays above the high watermark for the sustained period.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// Evictions counts pods evicted by the descheduler.
+	Evictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      DeschedulerSubsystem,
+			Name:           "evictions_total",
+			Help:           "Number of pods evicted from overloaded nodes by the descheduler, by result.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"result"})
+
+	metricsList = []metrics.Registerable{
+		QueryDuration,
+		QueryErrors,
+		PatchFailures,
+		LastSyncTimestamp,
+		BindingRecords,
+		OverloadedNodes,
+		Evictions,
+	}
+)
+
+var registerMetrics sync.Once
+
+// Register registers node annotator and descheduler metrics to the legacy registry.
+func Register() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
+
+// ObserveQuery records the latency and the result of a query.
+func ObserveQuery(metricName, mode string, startTime time.Time, err error) {
+	QueryDuration.WithLabelValues(metricName, mode).Observe(time.Since(startTime).Seconds())
+	if err != nil {
+		QueryErrors.WithLabelValues(metricName, mode).Inc()
+	}
+}
+
+// ObservePatch records the result of patching a metric to node annotations.
+func ObservePatch(metricName string, err error) {
+	if err != nil {
+		PatchFailures.WithLabelValues(metricName).Inc()
+		return
+	}
+	LastSyncTimestamp.WithLabelValues(metricName).SetToCurrentTime()
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestObserveQueryAndPatch(t *testing.T) {
+	Register()
+	QueryDuration.Reset()
+	QueryErrors.Reset()
+	PatchFailures.Reset()
+	LastSyncTimestamp.Reset()
+
+	ObserveQuery("cpu_usage_avg_5m", "node", time.Now(), nil)
+	ObserveQuery("cpu_usage_avg_5m", "batch", time.Now(), fmt.Errorf("timeout"))
+	ObservePatch("cpu_usage_avg_5m", fmt.Errorf("conflict"))
+	ObservePatch("mem_usage_avg_5m", nil)
+
+	expected := `
+# HELP crane_annotator_query_errors_total [ALPHA] Number of failed queries to the metrics provider, by metric and query mode.
+# TYPE crane_annotator_query_errors_total counter
+crane_annotator_query_errors_total{metric="cpu_usage_avg_5m",mode="batch"} 1
+# HELP crane_annotator_patch_failures_total [ALPHA] Number of failed patches of node annotations, by metric.
+# TYPE crane_annotator_patch_failures_total counter
+crane_annotator_patch_failures_total{metric="cpu_usage_avg_5m"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"crane_annotator_query_errors_total", "crane_annotator_patch_failures_total"); err != nil {
+		t.Error(err)
+	}
+
+	for _, mode := range []string{"node", "batch"} {
+		if count, err := testutil.GetHistogramMetricCount(QueryDuration.WithLabelValues("cpu_usage_avg_5m", mode)); err != nil || count != 1 {
+			t.Errorf("got %d queries of mode %s and error %v, want 1", count, mode, err)
+		}
+	}
+
+	// the timestamp is only updated by successful patches.
+	if value, err := testutil.GetGaugeMetricValue(LastSyncTimestamp.WithLabelValues("cpu_usage_avg_5m")); err != nil || value != 0 {
+		t.Errorf("got last sync timestamp %v and error %v of failed patch, want 0", value, err)
+	}
+	if value, err := testutil.GetGaugeMetricValue(LastSyncTimestamp.WithLabelValues("mem_usage_avg_5m")); err != nil || value == 0 {
+		t.Errorf("got last sync timestamp %v and error %v of successful patch, want the current time", value, err)
+	}
+}
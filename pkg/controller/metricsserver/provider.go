@@ -0,0 +1,153 @@
+package metricsserver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
+)
+
+const (
+	// DefaultPollInterval is the default interval of polling metrics.k8s.io API.
+	DefaultPollInterval = 30 * time.Second
+	// DefaultQueryTimeout is the timeout of listing node metrics.
+	DefaultQueryTimeout = 10 * time.Second
+)
+
+var _ provider.Runnable = &MetricsServerProvider{}
+
+// MetricsServerProvider is a MetricsProvider backed by the metrics.k8s.io API.
+// It polls NodeMetrics periodically and keeps samples in memory, so that average
+// and max average usage ratios over rolling windows can be computed locally,
+// e.g. cpu_usage_avg_5m and mem_usage_max_avg_1h, without recording rules.
+type MetricsServerProvider struct {
+	client       metricsclientset.Interface
+	pollInterval time.Duration
+	retention    time.Duration
+
+	rw sync.RWMutex
+	// samples stores absolute resource usage of nodes, indexed by node name and resource name.
+	samples map[string]map[v1.ResourceName][]sample
+}
+
+// NewMetricsProvider returns a MetricsServerProvider which is able to serve the given metrics.
+func NewMetricsProvider(client metricsclientset.Interface, pollInterval time.Duration, metricNames []string) (*MetricsServerProvider, error) {
+	var retention time.Duration
+
+	for _, name := range metricNames {
+		spec, err := parseMetricName(name)
+		if err != nil {
+			return nil, err
+		}
+		if spec.retention() > retention {
+			retention = spec.retention()
+		}
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	return &MetricsServerProvider{
+		client:       client,
+		pollInterval: pollInterval,
+		retention:    retention,
+		samples:      make(map[string]map[v1.ResourceName][]sample),
+	}, nil
+}
+
+// Run polls node metrics until stopCh is closed.
+func (m *MetricsServerProvider) Run(stopCh <-chan struct{}) {
+	klog.Infof("Start to poll node metrics every %v, retention is %v", m.pollInterval, m.retention)
+	wait.Until(m.poll, m.pollInterval, stopCh)
+}
+
+func (m *MetricsServerProvider) poll() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultQueryTimeout)
+	defer cancel()
+
+	nodeMetricsList, err := m.client.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.Errorf("Failed to list node metrics: %v", err)
+		return
+	}
+
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	nodes, expiration := sets.NewString(), time.Now().Add(-m.retention-m.pollInterval)
+	for _, nodeMetrics := range nodeMetricsList.Items {
+		nodes.Insert(nodeMetrics.Name)
+
+		if m.samples[nodeMetrics.Name] == nil {
+			m.samples[nodeMetrics.Name] = make(map[v1.ResourceName][]sample)
+		}
+
+		for _, resourceName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			usage, ok := nodeMetrics.Usage[resourceName]
+			if !ok {
+				continue
+			}
+			m.samples[nodeMetrics.Name][resourceName] = appendSample(m.samples[nodeMetrics.Name][resourceName],
+				sample{timestamp: nodeMetrics.Timestamp.Time, value: usage.AsApproximateFloat64()}, expiration)
+		}
+	}
+
+	// recycle samples of deleted nodes.
+	for name := range m.samples {
+		if !nodes.Has(name) {
+			delete(m.samples, name)
+		}
+	}
+}
+
+// appendSample appends the new sample if it is newer than the last one, and drops expired samples.
+func appendSample(samples []sample, s sample, expiration time.Time) []sample {
+	if len(samples) == 0 || s.timestamp.After(samples[len(samples)-1].timestamp) {
+		samples = append(samples, s)
+	}
+
+	var i int
+	for i < len(samples) && samples[i].timestamp.Before(expiration) {
+		i++
+	}
+
+	return samples[i:]
+}
+
+// QueryNodeMetric computes the usage ratio of the metric from samples and node allocatable.
+func (m *MetricsServerProvider) QueryNodeMetric(metricName string, node *v1.Node) (float64, error) {
+	spec, err := parseMetricName(metricName)
+	if err != nil {
+		return 0, err
+	}
+
+	allocatable, ok := node.Status.Allocatable[spec.resourceName]
+	if !ok || allocatable.IsZero() {
+		return 0, fmt.Errorf("allocatable of resource %s not found", spec.resourceName)
+	}
+
+	m.rw.RLock()
+	defer m.rw.RUnlock()
+
+	usage, err := spec.aggregate(m.samples[node.Name][spec.resourceName], time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute %s of node[%s]: %v", metricName, node.Name, err)
+	}
+
+	ratio := usage / allocatable.AsApproximateFloat64()
+	if ratio < 0 {
+		ratio = 0
+	}
+
+	return ratio, nil
+}
@@ -0,0 +1,151 @@
+package metricsserver
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultAvgWindow is the window of the inner average of max_avg metrics,
+	// which keeps the same with the recording rule cpu_usage_avg_5m.
+	DefaultAvgWindow = 5 * time.Minute
+)
+
+// aggregation is the way to aggregate samples in a window.
+type aggregation string
+
+const (
+	// aggregationLatest takes the latest sample, e.g. cpu_usage_active.
+	aggregationLatest aggregation = "active"
+	// aggregationAvg takes the average of samples, e.g. cpu_usage_avg_5m.
+	aggregationAvg aggregation = "avg"
+	// aggregationMaxAvg takes the max of DefaultAvgWindow averages, e.g. cpu_usage_max_avg_1h.
+	aggregationMaxAvg aggregation = "max_avg"
+)
+
+var metricNameRegexp = regexp.MustCompile(`^(cpu|mem)_usage_(active|avg|max_avg)(?:_([0-9]+[smhdwy]))?$`)
+
+// metricSpec describes how to compute a metric from samples.
+type metricSpec struct {
+	resourceName v1.ResourceName
+	aggregation  aggregation
+	window       time.Duration
+}
+
+// parseMetricName parses metric names such as cpu_usage_avg_5m and mem_usage_max_avg_1h.
+func parseMetricName(metricName string) (*metricSpec, error) {
+	matches := metricNameRegexp.FindStringSubmatch(metricName)
+	if matches == nil {
+		return nil, fmt.Errorf("metric %s is not supported by metrics-server", metricName)
+	}
+
+	spec := &metricSpec{
+		resourceName: v1.ResourceCPU,
+		aggregation:  aggregation(matches[2]),
+	}
+	if matches[1] == "mem" {
+		spec.resourceName = v1.ResourceMemory
+	}
+
+	if spec.aggregation == aggregationLatest {
+		if matches[3] != "" {
+			return nil, fmt.Errorf("metric %s should not have a window", metricName)
+		}
+		return spec, nil
+	}
+
+	if matches[3] == "" {
+		return nil, fmt.Errorf("window of metric %s is missing", metricName)
+	}
+
+	window, err := model.ParseDuration(matches[3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse window of metric %s: %v", metricName, err)
+	}
+	spec.window = time.Duration(window)
+
+	return spec, nil
+}
+
+// retention returns how long samples should be kept to compute this metric.
+func (m *metricSpec) retention() time.Duration {
+	if m.aggregation == aggregationMaxAvg {
+		return m.window + DefaultAvgWindow
+	}
+	return m.window
+}
+
+// sample is the resource usage of a node at the given time.
+type sample struct {
+	timestamp time.Time
+	value     float64
+}
+
+// aggregate computes the metric from samples sorted by timestamp.
+func (m *metricSpec) aggregate(samples []sample, now time.Time) (float64, error) {
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("no samples")
+	}
+
+	switch m.aggregation {
+	case aggregationAvg:
+		return average(samples, now.Add(-m.window))
+	case aggregationMaxAvg:
+		return maxAverage(samples, now.Add(-m.window), DefaultAvgWindow)
+	default:
+		return samples[len(samples)-1].value, nil
+	}
+}
+
+// average returns the average of samples after since.
+func average(samples []sample, since time.Time) (float64, error) {
+	var sum float64
+	var count int
+
+	for _, s := range samples {
+		if s.timestamp.After(since) {
+			sum += s.value
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, fmt.Errorf("no samples since %v", since)
+	}
+
+	return sum / float64(count), nil
+}
+
+// maxAverage returns the max of rolling averages over avgWindow, which are
+// evaluated at each sample after since.
+func maxAverage(samples []sample, since time.Time, avgWindow time.Duration) (float64, error) {
+	var max, sum float64
+	var found bool
+
+	for start, end := 0, 0; end < len(samples); end++ {
+		sum += samples[end].value
+		for !samples[start].timestamp.Add(avgWindow).After(samples[end].timestamp) {
+			sum -= samples[start].value
+			start++
+		}
+
+		if !samples[end].timestamp.After(since) {
+			continue
+		}
+
+		avg := sum / float64(end-start+1)
+		if !found || avg > max {
+			max, found = avg, true
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no samples since %v", since)
+	}
+
+	return max, nil
+}
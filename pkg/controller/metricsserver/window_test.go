@@ -0,0 +1,120 @@
+package metricsserver
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseMetricName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    metricSpec
+		wantErr bool
+	}{
+		{name: "cpu_usage_active", want: metricSpec{resourceName: v1.ResourceCPU, aggregation: aggregationLatest}},
+		{name: "cpu_usage_avg_5m", want: metricSpec{resourceName: v1.ResourceCPU, aggregation: aggregationAvg, window: 5 * time.Minute}},
+		{name: "mem_usage_max_avg_1d", want: metricSpec{resourceName: v1.ResourceMemory, aggregation: aggregationMaxAvg, window: 24 * time.Hour}},
+		{name: "mem_usage_avg", wantErr: true},
+		{name: "cpu_usage_active_5m", wantErr: true},
+		{name: "disk_usage_avg_5m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMetricName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error is %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricSpec_Aggregate(t *testing.T) {
+	now := time.Now()
+
+	// one sample per minute in the last 20 minutes, with value 1 for the
+	// first 10 minutes and value 3 for the last 10 minutes.
+	var samples []sample
+	for i := 19; i >= 0; i-- {
+		value := 3.
+		if i >= 10 {
+			value = 1
+		}
+		samples = append(samples, sample{timestamp: now.Add(-time.Duration(i) * time.Minute), value: value})
+	}
+
+	tests := []struct {
+		name string
+		spec metricSpec
+		want float64
+	}{
+		{
+			name: "latest",
+			spec: metricSpec{aggregation: aggregationLatest},
+			want: 3,
+		},
+		{
+			name: "average over 5m",
+			spec: metricSpec{aggregation: aggregationAvg, window: 5 * time.Minute},
+			want: 3,
+		},
+		{
+			name: "average over 20m",
+			spec: metricSpec{aggregation: aggregationAvg, window: 20 * time.Minute},
+			want: 2,
+		},
+		{
+			name: "max of 5m averages over 1h",
+			spec: metricSpec{aggregation: aggregationMaxAvg, window: time.Hour},
+			want: 3,
+		},
+		{
+			name: "max of 5m averages over 12m",
+			spec: metricSpec{aggregation: aggregationMaxAvg, window: 12 * time.Minute},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.aggregate(samples, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&metricSpec{aggregation: aggregationAvg, window: time.Minute}).aggregate(samples[:10], now); err == nil {
+		t.Errorf("expect error when no samples in window")
+	}
+}
+
+func TestMaxAverage(t *testing.T) {
+	now := time.Now()
+	samples := []sample{
+		{timestamp: now.Add(-10 * time.Minute), value: 1},
+		{timestamp: now.Add(-8 * time.Minute), value: 5},
+		{timestamp: now.Add(-6 * time.Minute), value: 3},
+		{timestamp: now.Add(-2 * time.Minute), value: 1},
+	}
+
+	// rolling 5m averages: 1, 3, 3, 2.
+	got, err := maxAverage(samples, now.Add(-time.Hour), 5*time.Minute)
+	if err != nil || got != 3 {
+		t.Errorf("got %v with error %v, want 3", got, err)
+	}
+
+	got, err = maxAverage(samples, now.Add(-5*time.Minute), 5*time.Minute)
+	if err != nil || got != 2 {
+		t.Errorf("got %v with error %v, want 2", got, err)
+	}
+}
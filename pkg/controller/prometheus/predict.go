@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// predictPeak returns the peak usage predicted at each step in (now, now+horizon]
+// from the history by the model of the prediction policy.
+func predictPeak(history []model.SamplePair, now time.Time, p policy.PredictionPolicy) (float64, error) {
+	var predict func(t time.Time) (float64, bool)
+
+	switch p.Model {
+	case policy.PredictionModelSeasonal:
+		predict = seasonalModel(history, now, p.Season.Duration, p.Step.Duration)
+	default:
+		predict = linearModel(history)
+	}
+
+	if predict == nil {
+		return 0, fmt.Errorf("not enough history to fit %s model, got %d samples", p.Model, len(history))
+	}
+
+	peak, found := math.Inf(-1), false
+	for t := now.Add(p.Step.Duration); !t.After(now.Add(p.Horizon.Duration)); t = t.Add(p.Step.Duration) {
+		if value, ok := predict(t); ok {
+			peak, found = math.Max(peak, value), true
+		}
+	}
+
+	if !found || math.IsNaN(peak) {
+		return 0, fmt.Errorf("no usage predicted within %v", p.Horizon.Duration)
+	}
+
+	return math.Max(peak, 0), nil
+}
+
+// linearModel fits the history by least squares, and returns the fitted line.
+func linearModel(history []model.SamplePair) func(t time.Time) (float64, bool) {
+	if len(history) < 2 {
+		return nil
+	}
+
+	origin := history[0].Timestamp.Time()
+	n := float64(len(history))
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range history {
+		x, y := sample.Timestamp.Time().Sub(origin).Seconds(), float64(sample.Value)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	return func(t time.Time) (float64, bool) {
+		return intercept + slope*t.Sub(origin).Seconds(), true
+	}
+}
+
+// seasonalModel returns the usage one season ago, shifted by the change of usage
+// between one season ago and now.
+func seasonalModel(history []model.SamplePair, now time.Time, season, step time.Duration) func(t time.Time) (float64, bool) {
+	if len(history) == 0 {
+		return nil
+	}
+
+	latest := float64(history[len(history)-1].Value)
+
+	var drift float64
+	if value, ok := sampleAt(history, now.Add(-season), step); ok {
+		drift = latest - value
+	}
+
+	return func(t time.Time) (float64, bool) {
+		value, ok := sampleAt(history, t.Add(-season), step)
+		return value + drift, ok
+	}
+}
+
+// sampleAt returns the value of the sample nearest to t, if it is within half a step.
+func sampleAt(history []model.SamplePair, t time.Time, step time.Duration) (float64, bool) {
+	var value float64
+	nearest := step/2 + 1
+
+	for _, sample := range history {
+		distance := sample.Timestamp.Time().Sub(t)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < nearest {
+			value, nearest = float64(sample.Value), distance
+		}
+	}
+
+	return value, nearest <= step/2
+}
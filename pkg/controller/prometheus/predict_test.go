@@ -0,0 +1,88 @@
+package prometheus
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+func TestPredictPeak(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	step := 5 * time.Minute
+
+	// history of the last 2 hours at each step, valued by value(age).
+	history := func(value func(age time.Duration) float64) []model.SamplePair {
+		var samples []model.SamplePair
+		for age := 2 * time.Hour; age >= 0; age -= step {
+			samples = append(samples, model.SamplePair{
+				Timestamp: model.TimeFromUnixNano(now.Add(-age).UnixNano()),
+				Value:     model.SampleValue(value(age)),
+			})
+		}
+		return samples
+	}
+
+	tests := []struct {
+		name    string
+		history []model.SamplePair
+		model   policy.PredictionModel
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:    "linear trend",
+			history: history(func(age time.Duration) float64 { return 0.5 - age.Hours()*0.1 }),
+			model:   policy.PredictionModelLinear,
+			want:    0.55,
+		},
+		{
+			name:    "linear trend going down is clamped",
+			history: history(func(age time.Duration) float64 { return age.Hours() * 0.01 }),
+			model:   policy.PredictionModelLinear,
+			want:    0,
+		},
+		{
+			name: "seasonal peak with drift",
+			// a peak of 0.8 one hour ago and 0.1 at other times, season of 1 hour.
+			history: history(func(age time.Duration) float64 {
+				if age == time.Hour-10*time.Minute {
+					return 0.8
+				}
+				if age == 0 {
+					return 0.2
+				}
+				return 0.1
+			}),
+			model: policy.PredictionModelSeasonal,
+			want:  0.9,
+		},
+		{
+			name:    "not enough history",
+			history: history(func(age time.Duration) float64 { return 0.5 })[:1],
+			model:   policy.PredictionModelLinear,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := predictPeak(tt.history, now, policy.PredictionPolicy{
+				Model:   tt.model,
+				Step:    metav1.Duration{Duration: step},
+				Horizon: metav1.Duration{Duration: 30 * time.Minute},
+				Season:  metav1.Duration{Duration: time.Hour},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("got peak %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,13 @@ type PromClient interface {
 	QueryByNodeName(string, string) (string, error)
 	// QueryByNodeIPWithOffset queries data by node IP with offset.
 	QueryByNodeIPWithOffset(string, string, string) (string, error)
+	// QueryByPromQL queries data by the given PromQL.
+	QueryByPromQL(string) (string, error)
+	// QueryAllInstances queries data of all instances, indexed by the instance label.
+	QueryAllInstances(string) (map[string]string, error)
+	// QueryRangeByPromQL queries samples of the first series of the given PromQL
+	// within the time range.
+	QueryRangeByPromQL(string, v1.Range) ([]model.SamplePair, error)
 }
 
 type promClient struct {
@@ -97,7 +104,83 @@ func (p *promClient) QueryByNodeIPWithOffset(metricName, ip, offset string) (str
 	return "", err
 }
 
+func (p *promClient) QueryByPromQL(query string) (string, error) {
+	result, err := p.query(query)
+	if result != "" && err == nil {
+		return result, nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("empty result of query %s", query)
+	}
+
+	return "", err
+}
+
+func (p *promClient) QueryAllInstances(metricName string) (map[string]string, error) {
+	klog.V(4).Infof("Try to query %s of all instances", metricName)
+
+	querySelector := fmt.Sprintf("%s /100", metricName)
+
+	vector, err := p.queryVector(querySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]string, len(vector))
+	for _, elem := range vector {
+		instance := string(elem.Metric[model.InstanceLabel])
+		if instance == "" {
+			continue
+		}
+		results[instance] = formatSampleValue(elem.Value)
+	}
+
+	return results, nil
+}
+
+func (p *promClient) QueryRangeByPromQL(query string, r v1.Range) ([]model.SamplePair, error) {
+	klog.V(4).Infof("Begin to query prometheus by promQL [%s] from %v to %v...", query, r.Start, r.End)
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultPrometheusQueryTimeout)
+	defer cancel()
+
+	result, warnings, err := p.API.QueryRange(ctx, query, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(warnings) > 0 {
+		return nil, fmt.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	if result.Type() != model.ValMatrix {
+		return nil, fmt.Errorf("illege result type: %v", result.Type())
+	}
+
+	matrix := result.(model.Matrix)
+	if len(matrix) == 0 || len(matrix[0].Values) == 0 {
+		return nil, nil
+	}
+
+	return matrix[0].Values, nil
+}
+
 func (p *promClient) query(query string) (string, error) {
+	vector, err := p.queryVector(query)
+	if err != nil {
+		return "", err
+	}
+
+	var metricValue string
+	for _, elem := range vector {
+		metricValue = formatSampleValue(elem.Value)
+	}
+
+	return metricValue, nil
+}
+
+func (p *promClient) queryVector(query string) (model.Vector, error) {
 	klog.V(4).Infof("Begin to query prometheus by promQL [%s]...", query)
 
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultPrometheusQueryTimeout)
@@ -105,24 +188,24 @@ func (p *promClient) query(query string) (string, error) {
 
 	result, warnings, err := p.API.Query(ctx, query, time.Now())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(warnings) > 0 {
-		return "", fmt.Errorf("unexpected warnings: %v", warnings)
+		return nil, fmt.Errorf("unexpected warnings: %v", warnings)
 	}
 
 	if result.Type() != model.ValVector {
-		return "", fmt.Errorf("illege result type: %v", result.Type())
+		return nil, fmt.Errorf("illege result type: %v", result.Type())
 	}
 
-	var metricValue string
-	for _, elem := range result.(model.Vector) {
-		if float64(elem.Value) < float64(0) || math.IsNaN(float64(elem.Value)) {
-			elem.Value = 0
-		}
-		metricValue = strconv.FormatFloat(float64(elem.Value), 'f', 5, 64)
+	return result.(model.Vector), nil
+}
+
+func formatSampleValue(value model.SampleValue) string {
+	if float64(value) < float64(0) || math.IsNaN(float64(value)) {
+		value = 0
 	}
 
-	return metricValue, nil
+	return strconv.FormatFloat(float64(value), 'f', 5, 64)
 }
@@ -0,0 +1,263 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"text/template"
+	"time"
+
+	v1api "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/controller/provider"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// queryTemplate is the compiled PromQL template of one metric.
+type queryTemplate struct {
+	template *template.Template
+	scale    float64
+}
+
+// QueryTemplateData is the data available in PromQL templates.
+type QueryTemplateData struct {
+	NodeIP   string
+	NodeName string
+	Labels   map[string]string
+}
+
+type promProvider struct {
+	client      PromClient
+	templates   map[string]*queryTemplate
+	predictions map[string]policy.PredictionPolicy
+}
+
+// NewMetricsProvider returns a MetricsProvider backed by Prometheus. Metrics with
+// PromQL templates in sync policies are queried by the rendered templates, and
+// metrics with predictions are the peak usage predicted from their history.
+func NewMetricsProvider(client PromClient, syncPolicies []policy.SyncPolicy) (provider.MetricsProvider, error) {
+	templates := make(map[string]*queryTemplate)
+	predictions := make(map[string]policy.PredictionPolicy)
+
+	for _, p := range syncPolicies {
+		if p.Prediction != nil {
+			predictions[p.Name] = policy.GetPredictionPolicyWithDefaults(p.Prediction)
+		}
+
+		if p.Query == "" {
+			continue
+		}
+
+		tmpl, err := ParseQueryTemplate(p.Name, p.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		scale := p.Scale
+		if scale == 0 {
+			scale = 1
+		}
+
+		templates[p.Name] = &queryTemplate{template: tmpl, scale: scale}
+	}
+
+	return &promProvider{
+		client:      client,
+		templates:   templates,
+		predictions: predictions,
+	}, nil
+}
+
+// ParseQueryTemplate parses the PromQL template of the metric.
+func ParseQueryTemplate(metricName, query string) (*template.Template, error) {
+	tmpl, err := template.New(metricName).Option("missingkey=error").Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query template of metric %s: %v", metricName, err)
+	}
+
+	return tmpl, nil
+}
+
+// QueryNodeMetric predicts the metric from its history if it has a prediction,
+// or queries the metric by its PromQL template if exists, otherwise by node IP
+// first, and then by node name.
+func (p *promProvider) QueryNodeMetric(metricName string, node *v1.Node) (float64, error) {
+	if prediction, ok := p.predictions[metricName]; ok {
+		return p.queryPrediction(metricName, prediction, node)
+	}
+
+	if tmpl, ok := p.templates[metricName]; ok {
+		return p.queryByTemplate(tmpl, node)
+	}
+
+	value, err := p.client.QueryByNodeIP(metricName, getNodeInternalIP(node))
+	if err != nil || len(value) == 0 {
+		value, err = p.client.QueryByNodeName(metricName, node.Name)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(value) == 0 {
+		return 0, fmt.Errorf("no data of %s found for node[%s]", metricName, node.Name)
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
+// SupportsBatchQuery returns false for metrics with PromQL templates, as they are
+// rendered for each node, and for metrics with predictions, as they are predicted
+// from the history of each node.
+func (p *promProvider) SupportsBatchQuery(metricName string) bool {
+	if _, ok := p.templates[metricName]; ok {
+		return false
+	}
+	_, ok := p.predictions[metricName]
+	return !ok
+}
+
+// QueryNodesMetric queries the metric of all instances by one query, and maps
+// series back to nodes by the instance label, which is either node IP or node
+// name with an optional port. Metrics with PromQL templates or predictions are
+// not supported.
+func (p *promProvider) QueryNodesMetric(metricName string, nodes []*v1.Node) (map[string]float64, error) {
+	if _, ok := p.templates[metricName]; ok {
+		return nil, fmt.Errorf("batch query is not supported by metric %s with query template", metricName)
+	}
+	if _, ok := p.predictions[metricName]; ok {
+		return nil, fmt.Errorf("batch query is not supported by metric %s with prediction", metricName)
+	}
+
+	instances, err := p.client.QueryAllInstances(metricName)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(instances))
+	for instance, value := range instances {
+		if host, _, err := net.SplitHostPort(instance); err == nil {
+			instance = host
+		}
+		values[instance] = value
+	}
+
+	results := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		value, ok := values[getNodeInternalIP(node)]
+		if !ok {
+			value, ok = values[node.Name]
+		}
+		if !ok {
+			continue
+		}
+
+		result, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			klog.Warningf("Failed to parse %s of node[%s]: %v", metricName, node.Name, err)
+			continue
+		}
+		results[node.Name] = result
+	}
+
+	return results, nil
+}
+
+func (p *promProvider) queryByTemplate(tmpl *queryTemplate, node *v1.Node) (float64, error) {
+	query, err := renderQuery(tmpl, node)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := p.client.QueryByPromQL(query)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return result * tmpl.scale, nil
+}
+
+// queryPrediction queries the history of the metric within the lookback, and
+// returns the peak usage predicted within the horizon.
+func (p *promProvider) queryPrediction(metricName string, prediction policy.PredictionPolicy, node *v1.Node) (float64, error) {
+	now := time.Now()
+	r := v1api.Range{
+		Start: now.Add(-prediction.Lookback.Duration),
+		End:   now,
+		Step:  prediction.Step.Duration,
+	}
+
+	var queries []string
+	scale := 1.0
+
+	if tmpl, ok := p.templates[metricName]; ok {
+		query, err := renderQuery(tmpl, node)
+		if err != nil {
+			return 0, err
+		}
+		queries, scale = []string{query}, tmpl.scale
+	} else {
+		ip := getNodeInternalIP(node)
+		queries = []string{
+			fmt.Sprintf("%s{instance=~\"%s\"} /100", prediction.Metric, ip),
+			fmt.Sprintf("%s{instance=~\"%s:.+\"} /100", prediction.Metric, ip),
+			fmt.Sprintf("%s{instance=~\"%s\"} /100", prediction.Metric, node.Name),
+		}
+	}
+
+	var (
+		history []model.SamplePair
+		err     error
+	)
+	for _, query := range queries {
+		history, err = p.client.QueryRangeByPromQL(query, r)
+		if err == nil && len(history) > 0 {
+			break
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(history) == 0 {
+		return 0, fmt.Errorf("no history of %s found for node[%s]", metricName, node.Name)
+	}
+
+	peak, err := predictPeak(history, now, prediction)
+	if err != nil {
+		return 0, fmt.Errorf("failed to predict %s of node[%s]: %v", metricName, node.Name, err)
+	}
+
+	return peak * scale, nil
+}
+
+func renderQuery(tmpl *queryTemplate, node *v1.Node) (string, error) {
+	var buf bytes.Buffer
+
+	err := tmpl.template.Execute(&buf, QueryTemplateData{
+		NodeIP:   getNodeInternalIP(node),
+		NodeName: node.Name,
+		Labels:   node.Labels,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render query template of metric %s: %v", tmpl.template.Name(), err)
+	}
+
+	return buf.String(), nil
+}
+
+func getNodeInternalIP(node *v1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == v1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+
+	return node.Name
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ProviderType is the type of metrics backend used by the node annotator.
+type ProviderType string
+
+const (
+	// PrometheusProvider pulls metrics data from Prometheus.
+	PrometheusProvider ProviderType = "prometheus"
+	// MetricsServerProvider pulls metrics data from the metrics.k8s.io API served by metrics-server.
+	MetricsServerProvider ProviderType = "metrics-server"
+	// StaticProvider reads metrics data from a static file, which is mostly used for tests.
+	StaticProvider ProviderType = "static"
+)
+
+// MetricsProvider provides node load data for the node annotator.
+type MetricsProvider interface {
+	// QueryNodeMetric returns the value of the metric on the specified node,
+	// which is expected to be a usage ratio in range [0, 1].
+	QueryNodeMetric(metricName string, node *v1.Node) (float64, error)
+}
+
+// BatchMetricsProvider is implemented by providers which are able to query one
+// metric of all nodes at once, instead of issuing one query per node.
+type BatchMetricsProvider interface {
+	// QueryNodesMetric returns the values of the metric on the given nodes,
+	// indexed by node name. Nodes without data are absent from the result.
+	QueryNodesMetric(metricName string, nodes []*v1.Node) (map[string]float64, error)
+	// SupportsBatchQuery returns true if the metric can be queried by QueryNodesMetric,
+	// otherwise it is synced node by node.
+	SupportsBatchQuery(metricName string) bool
+}
+
+// Runnable is implemented by providers which have background work to do, such
+// as polling metrics, and is started along with the node annotator.
+type Runnable interface {
+	// Run runs until stopCh is closed.
+	Run(stopCh <-chan struct{})
+}
@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// StaticMetrics maps node names to the values of their metrics.
+type StaticMetrics map[string]map[string]float64
+
+// StaticMetricsProvider is a MetricsProvider serving metrics data kept in memory.
+type StaticMetricsProvider struct {
+	rw      sync.RWMutex
+	metrics StaticMetrics
+}
+
+// NewStaticProvider returns a MetricsProvider serving the given metrics data,
+// which can also be changed by SetNodeMetric later.
+func NewStaticProvider(metrics StaticMetrics) *StaticMetricsProvider {
+	if metrics == nil {
+		metrics = StaticMetrics{}
+	}
+
+	return &StaticMetricsProvider{
+		metrics: metrics,
+	}
+}
+
+// NewStaticProviderFromFile returns a MetricsProvider serving the metrics data
+// read from a YAML or JSON file, such as:
+//
+//	node-1:
+//	  cpu_usage_avg_5m: 0.3
+//	  mem_usage_avg_5m: 0.5
+func NewStaticProviderFromFile(file string) (MetricsProvider, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := StaticMetrics{}
+	if err := yaml.Unmarshal(data, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode static metrics file %s: %v", file, err)
+	}
+
+	return NewStaticProvider(metrics), nil
+}
+
+// QueryNodeMetric returns the static value of the metric on the specified node.
+func (s *StaticMetricsProvider) QueryNodeMetric(metricName string, node *v1.Node) (float64, error) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	value, ok := s.metrics[node.Name][metricName]
+	if !ok {
+		return 0, fmt.Errorf("metric %s of node[%s] not found", metricName, node.Name)
+	}
+
+	return value, nil
+}
+
+// SetNodeMetric sets the value of the metric on the specified node.
+func (s *StaticMetricsProvider) SetNodeMetric(nodeName, metricName string, value float64) {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+
+	if s.metrics[nodeName] == nil {
+		s.metrics[nodeName] = map[string]float64{}
+	}
+
+	s.metrics[nodeName][metricName] = value
+}
+
+// SupportsBatchQuery returns true as all static metrics can be queried at once.
+func (s *StaticMetricsProvider) SupportsBatchQuery(metricName string) bool {
+	return true
+}
+
+// QueryNodesMetric returns the static values of the metric on the given nodes.
+func (s *StaticMetricsProvider) QueryNodesMetric(metricName string, nodes []*v1.Node) (map[string]float64, error) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	results := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		if value, ok := s.metrics[node.Name][metricName]; ok {
+			results[node.Name] = value
+		}
+	}
+
+	return results, nil
+}
@@ -11,6 +11,9 @@ type DynamicArgs struct {
 	metav1.TypeMeta
 	// PolicyConfigPath specified the path of policy config.
 	PolicyConfigPath string
+	// BindingHeapSize limits the number of recent bindings kept by the plugin to
+	// compute hot values.
+	BindingHeapSize int32
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
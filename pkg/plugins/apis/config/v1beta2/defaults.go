@@ -2,12 +2,17 @@ package v1beta2
 
 var (
 	defaultNodeResource = []string{"cpu"}
+
+	defaultBindingHeapSize int32 = 1024
 )
 
 func SetDefaults_DynamicArgs(obj *DynamicArgs) {
 	if obj.PolicyConfigPath == "" {
 		obj.PolicyConfigPath = "/etc/kubernetes/dynamic-scheduler-policy.yaml"
 	}
+	if obj.BindingHeapSize == 0 {
+		obj.BindingHeapSize = defaultBindingHeapSize
+	}
 	return
 }
 
@@ -45,6 +45,7 @@ func RegisterConversions(s *runtime.Scheme) error {
 
 func autoConvert_v1beta2_DynamicArgs_To_config_DynamicArgs(in *DynamicArgs, out *config.DynamicArgs, s conversion.Scope) error {
 	out.PolicyConfigPath = in.PolicyConfigPath
+	out.BindingHeapSize = in.BindingHeapSize
 	return nil
 }
 
@@ -55,6 +56,7 @@ func Convert_v1beta2_DynamicArgs_To_config_DynamicArgs(in *DynamicArgs, out *con
 
 func autoConvert_config_DynamicArgs_To_v1beta2_DynamicArgs(in *config.DynamicArgs, out *DynamicArgs, s conversion.Scope) error {
 	out.PolicyConfigPath = in.PolicyConfigPath
+	out.BindingHeapSize = in.BindingHeapSize
 	return nil
 }
 
@@ -2,6 +2,8 @@ package v1beta3
 
 var (
 	defaultNodeResource = []string{"cpu"}
+
+	defaultBindingHeapSize int32 = 1024
 )
 
 func SetDefaults_DynamicArgs(obj *DynamicArgs) {
@@ -9,6 +11,10 @@ func SetDefaults_DynamicArgs(obj *DynamicArgs) {
 		path := "/etc/kubernetes/dynamic-scheduler-policy.yaml"
 		obj.PolicyConfigPath = &path
 	}
+	if obj.BindingHeapSize == nil {
+		size := defaultBindingHeapSize
+		obj.BindingHeapSize = &size
+	}
 	return
 }
 
@@ -11,6 +11,9 @@ type DynamicArgs struct {
 	metav1.TypeMeta `json:",inline"`
 	// PolicyConfigPath specified the path of policy config.
 	PolicyConfigPath *string `json:"policyConfigPath,omitempty"`
+	// BindingHeapSize limits the number of recent bindings kept by the plugin to
+	// compute hot values.
+	BindingHeapSize *int32 `json:"bindingHeapSize,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
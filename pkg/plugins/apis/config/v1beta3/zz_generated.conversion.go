@@ -48,6 +48,9 @@ func autoConvert_v1beta3_DynamicArgs_To_config_DynamicArgs(in *DynamicArgs, out
 	if err := v1.Convert_Pointer_string_To_string(&in.PolicyConfigPath, &out.PolicyConfigPath, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.BindingHeapSize, &out.BindingHeapSize, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -60,6 +63,9 @@ func autoConvert_config_DynamicArgs_To_v1beta3_DynamicArgs(in *config.DynamicArg
 	if err := v1.Convert_string_To_Pointer_string(&in.PolicyConfigPath, &out.PolicyConfigPath, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.BindingHeapSize, &out.BindingHeapSize, s); err != nil {
+		return err
+	}
 	return nil
 }
 
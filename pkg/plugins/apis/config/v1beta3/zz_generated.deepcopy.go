@@ -18,6 +18,11 @@ func (in *DynamicArgs) DeepCopyInto(out *DynamicArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.BindingHeapSize != nil {
+		in, out := &in.BindingHeapSize, &out.BindingHeapSize
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
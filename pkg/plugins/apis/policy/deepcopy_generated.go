@@ -6,9 +6,47 @@
 package policy
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnticipatedLoadPolicy) DeepCopyInto(out *AnticipatedLoadPolicy) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AnticipatedMetric, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnticipatedLoadPolicy.
+func (in *AnticipatedLoadPolicy) DeepCopy() *AnticipatedLoadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AnticipatedLoadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnticipatedMetric) DeepCopyInto(out *AnticipatedMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnticipatedMetric.
+func (in *AnticipatedMetric) DeepCopy() *AnticipatedMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AnticipatedMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicSchedulerPolicy) DeepCopyInto(out *DynamicSchedulerPolicy) {
 	*out = *in
@@ -38,7 +76,12 @@ func (in *DynamicSchedulerPolicy) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HotValuePolicy) DeepCopyInto(out *HotValuePolicy) {
 	*out = *in
-	in.TimeRange.DeepCopyInto(&out.TimeRange)
+	out.TimeRange = in.TimeRange
+	if in.PenaltyFactor != nil {
+		in, out := &in.PenaltyFactor, &out.PenaltyFactor
+		*out = new(float64)
+		**out = **in
+	}
 	return
 }
 
@@ -52,6 +95,96 @@ func (in *HotValuePolicy) DeepCopy() *HotValuePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncomingPodLoadPolicy) DeepCopyInto(out *IncomingPodLoadPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncomingPodLoadPolicy.
+func (in *IncomingPodLoadPolicy) DeepCopy() *IncomingPodLoadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IncomingPodLoadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolPolicy) DeepCopyInto(out *NodePoolPolicy) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncPeriod != nil {
+		in, out := &in.SyncPeriod, &out.SyncPeriod
+		*out = make([]SyncPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Predicate != nil {
+		in, out := &in.Predicate, &out.Predicate
+		*out = make([]PredicatePolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = make([]PriorityPolicy, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolPolicy.
+func (in *NodePoolPolicy) DeepCopy() *NodePoolPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyOverride) DeepCopyInto(out *PolicyOverride) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Predicate != nil {
+		in, out := &in.Predicate, &out.Predicate
+		*out = make([]PredicatePolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = make([]PriorityPolicy, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyOverride.
+func (in *PolicyOverride) DeepCopy() *PolicyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 	*out = *in
@@ -79,6 +212,40 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AnticipatedLoad != nil {
+		in, out := &in.AnticipatedLoad, &out.AnticipatedLoad
+		*out = new(AnticipatedLoadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IncomingPodLoad != nil {
+		in, out := &in.IncomingPodLoad, &out.IncomingPodLoad
+		*out = new(IncomingPodLoadPolicy)
+		**out = **in
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(PostFilterPolicy)
+		**out = **in
+	}
+	if in.SafetyValve != nil {
+		in, out := &in.SafetyValve, &out.SafetyValve
+		*out = new(SafetyValvePolicy)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]PolicyOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -92,6 +259,22 @@ func (in *PolicySpec) DeepCopy() *PolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostFilterPolicy) DeepCopyInto(out *PostFilterPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostFilterPolicy.
+func (in *PostFilterPolicy) DeepCopy() *PostFilterPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PostFilterPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PredicatePolicy) DeepCopyInto(out *PredicatePolicy) {
 	*out = *in
@@ -108,6 +291,26 @@ func (in *PredicatePolicy) DeepCopy() *PredicatePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PredictionPolicy) DeepCopyInto(out *PredictionPolicy) {
+	*out = *in
+	out.Lookback = in.Lookback
+	out.Step = in.Step
+	out.Horizon = in.Horizon
+	out.Season = in.Season
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictionPolicy.
+func (in *PredictionPolicy) DeepCopy() *PredictionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PredictionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PriorityPolicy) DeepCopyInto(out *PriorityPolicy) {
 	*out = *in
@@ -124,10 +327,31 @@ func (in *PriorityPolicy) DeepCopy() *PriorityPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SafetyValvePolicy) DeepCopyInto(out *SafetyValvePolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafetyValvePolicy.
+func (in *SafetyValvePolicy) DeepCopy() *SafetyValvePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SafetyValvePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncPolicy) DeepCopyInto(out *SyncPolicy) {
 	*out = *in
-	in.Period.DeepCopyInto(&out.Period)
+	out.Period = in.Period
+	if in.Prediction != nil {
+		in, out := &in.Prediction, &out.Prediction
+		*out = new(PredictionPolicy)
+		**out = **in
+	}
 	return
 }
 
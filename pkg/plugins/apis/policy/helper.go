@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NodePoolSelector finds the node pool which a node belongs to.
+// +k8s:deepcopy-gen=false
+type NodePoolSelector struct {
+	pools     []NodePoolPolicy
+	selectors []labels.Selector
+}
+
+// NewNodePoolSelector parses node selectors of node pools, skipping illegal ones.
+func NewNodePoolSelector(pools []NodePoolPolicy) *NodePoolSelector {
+	s := &NodePoolSelector{}
+
+	for _, pool := range pools {
+		selector, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+		if err != nil {
+			continue
+		}
+		s.pools = append(s.pools, pool)
+		s.selectors = append(s.selectors, selector)
+	}
+
+	return s
+}
+
+// Select returns the first node pool selecting the node labels, or nil if none.
+func (s *NodePoolSelector) Select(nodeLabels map[string]string) *NodePoolPolicy {
+	if s == nil {
+		return nil
+	}
+
+	for i, selector := range s.selectors {
+		if selector.Matches(labels.Set(nodeLabels)) {
+			return &s.pools[i]
+		}
+	}
+
+	return nil
+}
+
+// ApplyNodePool returns the spec applied to nodes in the node pool, or the spec
+// itself if pool is nil.
+func ApplyNodePool(spec PolicySpec, pool *NodePoolPolicy) PolicySpec {
+	if pool == nil {
+		return spec
+	}
+
+	if pool.SyncPeriod != nil {
+		spec.SyncPeriod = pool.SyncPeriod
+	}
+	if pool.Predicate != nil {
+		spec.Predicate = pool.Predicate
+	}
+	if pool.Priority != nil {
+		spec.Priority = pool.Priority
+	}
+
+	return spec
+}
+
+// GetAllSyncPolicies returns sync policies of the spec and all node pools,
+// deduplicated by metric name and period.
+func GetAllSyncPolicies(spec *PolicySpec) []SyncPolicy {
+	type syncKey struct {
+		name   string
+		period metav1.Duration
+	}
+
+	var syncPolicies []SyncPolicy
+	seen := map[syncKey]bool{}
+
+	add := func(policies []SyncPolicy) {
+		for _, p := range policies {
+			key := syncKey{name: p.Name, period: p.Period}
+			if !seen[key] {
+				seen[key] = true
+				syncPolicies = append(syncPolicies, p)
+			}
+		}
+	}
+
+	add(spec.SyncPeriod)
+	for _, pool := range spec.NodePools {
+		add(pool.SyncPeriod)
+	}
+
+	return syncPolicies
+}
+
+const (
+	// DefaultPredictionLookback is the time range of the history used for prediction.
+	DefaultPredictionLookback = 24 * time.Hour
+	// DefaultPredictionStep is the resolution of the history and the prediction.
+	DefaultPredictionStep = 5 * time.Minute
+	// DefaultPredictionSeason is the period of the Seasonal prediction model.
+	DefaultPredictionSeason = 24 * time.Hour
+)
+
+// GetPredictionPolicyWithDefaults returns a copy of the prediction policy, whose
+// fields not set are filled with defaults.
+func GetPredictionPolicyWithDefaults(p *PredictionPolicy) PredictionPolicy {
+	out := *p
+
+	if out.Model == "" {
+		out.Model = PredictionModelLinear
+	}
+	if out.Lookback.Duration == 0 {
+		out.Lookback.Duration = DefaultPredictionLookback
+	}
+	if out.Step.Duration == 0 {
+		out.Step.Duration = DefaultPredictionStep
+	}
+	if out.Season.Duration == 0 {
+		out.Season.Duration = DefaultPredictionSeason
+	}
+
+	return out
+}
+
+// ExtraActivePeriod gives extra active time to load annotations beyond their sync period.
+const ExtraActivePeriod = 5 * time.Minute
+
+// GetActiveDuration returns how long the load annotation of the metric stays valid,
+// which is its sync period plus ExtraActivePeriod.
+func GetActiveDuration(syncPeriodList []SyncPolicy, name string) (time.Duration, error) {
+	for _, period := range syncPeriodList {
+		if period.Name == name {
+			if period.Period.Duration != 0 {
+				return period.Period.Duration + ExtraActivePeriod, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("failed to get the active duration")
+}
+
+// GetMaxHotValueTimeRange returns the longest time range of hot value policies.
+func GetMaxHotValueTimeRange(hotValues []HotValuePolicy) time.Duration {
+	var max time.Duration
+
+	for _, p := range hotValues {
+		if max < p.TimeRange.Duration {
+			max = p.TimeRange.Duration
+		}
+	}
+
+	return max
+}
+
+// GetHotValue returns the hot value of a node from ages of its recent bindings, which
+// is the sum of the penalty of each hot value policy, scaled by its PenaltyFactor.
+// It is the score deducted from the node as is.
+func GetHotValue(hotValues []HotValuePolicy, bindingAges []time.Duration) float64 {
+	var penalty float64
+
+	for i := range hotValues {
+		penalty += hotValues[i].penalty(bindingAges)
+	}
+
+	return penalty
+}
+
+// penalty returns the score deducted for bindings within the time range.
+func (p *HotValuePolicy) penalty(bindingAges []time.Duration) float64 {
+	if p.Count <= 0 {
+		return 0
+	}
+
+	var bindings float64
+	for _, age := range bindingAges {
+		if age >= p.TimeRange.Duration {
+			continue
+		}
+		if p.Aggregation == HotValueAggregationExponentialDecay {
+			bindings += math.Exp(-float64(age) / float64(p.TimeRange.Duration))
+		} else {
+			bindings++
+		}
+	}
+
+	value := bindings / float64(p.Count)
+	if p.Aggregation == "" || p.Aggregation == HotValueAggregationFloor {
+		value = math.Floor(value)
+	}
+
+	factor := float64(DefaultHotValuePenaltyFactor)
+	if p.PenaltyFactor != nil {
+		factor = *p.PenaltyFactor
+	}
+
+	penalty := value * factor
+	if p.MaxPenalty > 0 && penalty > p.MaxPenalty {
+		penalty = p.MaxPenalty
+	}
+
+	return penalty
+}
@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetHotValue(t *testing.T) {
+	ages := []time.Duration{0, 10 * time.Second, 20 * time.Second, 30 * time.Second, 5 * time.Minute}
+	minute := metav1.Duration{Duration: time.Minute}
+	five, zero := 5.0, 0.0
+
+	tests := []struct {
+		name      string
+		hotValues []HotValuePolicy
+		want      float64
+	}{
+		{
+			name:      "floor by default",
+			hotValues: []HotValuePolicy{{TimeRange: minute, Count: 5}},
+			want:      0,
+		},
+		{
+			name:      "fraction",
+			hotValues: []HotValuePolicy{{TimeRange: minute, Count: 5, Aggregation: HotValueAggregationFraction}},
+			want:      8,
+		},
+		{
+			name:      "exponential decay",
+			hotValues: []HotValuePolicy{{TimeRange: minute, Count: 1, Aggregation: HotValueAggregationExponentialDecay}},
+			want:      10 * (1 + math.Exp(-1.0/6) + math.Exp(-2.0/6) + math.Exp(-3.0/6)),
+		},
+		{
+			name: "penalty factor and cap",
+			hotValues: []HotValuePolicy{
+				{TimeRange: minute, Count: 2, PenaltyFactor: &five},
+				{TimeRange: metav1.Duration{Duration: 10 * time.Minute}, Count: 1, MaxPenalty: 30},
+			},
+			want: 10 + 30,
+		},
+		{
+			name: "zero penalty factor",
+			hotValues: []HotValuePolicy{
+				{TimeRange: minute, Count: 2, PenaltyFactor: &zero},
+				{TimeRange: minute, Count: 1},
+			},
+			want: 40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetHotValue(tt.hotValues, ages); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got hot value %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
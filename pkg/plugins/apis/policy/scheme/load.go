@@ -0,0 +1,34 @@
+package scheme
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	dynamicpolicy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// LoadPolicyFromFile decodes the DynamicSchedulerPolicy in file into the internal type.
+func LoadPolicyFromFile(file string) (*dynamicpolicy.DynamicSchedulerPolicy, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadPolicy(data)
+}
+
+// LoadPolicy decodes the DynamicSchedulerPolicy in data into the internal type.
+func LoadPolicy(data []byte) (*dynamicpolicy.DynamicSchedulerPolicy, error) {
+	// The UniversalDecoder runs defaulting and returns the internal type by default.
+	obj, gvk, err := Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if policyObj, ok := obj.(*dynamicpolicy.DynamicSchedulerPolicy); ok {
+		policyObj.TypeMeta.APIVersion = gvk.GroupVersion().String()
+		return policyObj, nil
+	}
+
+	return nil, fmt.Errorf("couldn't decode as DynamicSchedulerPolicy, got %s: ", gvk)
+}
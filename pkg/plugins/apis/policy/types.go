@@ -16,24 +16,242 @@ type PolicySpec struct {
 	Predicate  []PredicatePolicy
 	Priority   []PriorityPolicy
 	HotValue   []HotValuePolicy
+	// ScoreNormalization is how node scores are normalized across candidate nodes
+	// at the NormalizeScore stage. Scores are not normalized if not set.
+	ScoreNormalization ScoreNormalization
+	// PriorityWeighting is how weights of priorities are decided for each pod,
+	// which is Static if not set.
+	PriorityWeighting PriorityWeighting
+	// AnticipatedLoad adds the estimated load of pods bound recently to the usage
+	// of metrics, which is not reflected by metrics yet.
+	AnticipatedLoad *AnticipatedLoadPolicy
+	// IncomingPodLoad adds the estimated load of the pod being scheduled to the usage
+	// of metrics at Filter, so that nodes which the pod would overload are rejected.
+	IncomingPodLoad *IncomingPodLoadPolicy
+	// PostFilter reports the least loaded nodes when all nodes are rejected for
+	// their load, and optionally nominates one of them.
+	PostFilter *PostFilterPolicy
+	// SafetyValve degrades Filter to score-only mode when too many nodes are
+	// overloaded, which usually means that metrics are broken.
+	SafetyValve *SafetyValvePolicy
+	// Overrides are named policies applied to selected pods instead of the
+	// predicates and priorities above.
+	Overrides []PolicyOverride
+	// NodePools apply their own rules to nodes selected by labels, instead of the
+	// sync policies, predicates and priorities above.
+	NodePools []NodePoolPolicy
+}
+
+// NodePoolPolicy is the rule set of nodes selected by NodeSelector. Nodes belong
+// to the first node pool selecting them.
+type NodePoolPolicy struct {
+	Name         string
+	NodeSelector *metav1.LabelSelector
+	// SyncPeriod replaces the sync policies for nodes in the pool if not nil.
+	SyncPeriod []SyncPolicy
+	// Predicate replaces the predicates for nodes in the pool if not nil.
+	Predicate []PredicatePolicy
+	// Priority replaces the priorities for nodes in the pool if not nil.
+	Priority []PriorityPolicy
+}
+
+// PolicyOverride overrides predicates and priorities for pods which choose it by
+// annotation, or are selected by its namespaces and pod selector.
+type PolicyOverride struct {
+	Name string
+	// Namespaces selects pods in these namespaces, or in all namespaces if empty.
+	Namespaces []string
+	// PodSelector selects pods by labels. The override can only be chosen by
+	// annotation if neither Namespaces nor PodSelector is set.
+	PodSelector *metav1.LabelSelector
+	// IgnoreLoad makes the Dynamic plugin neither filter nor score nodes by load.
+	IgnoreLoad bool
+	// Predicate replaces the predicates of the policy if not nil.
+	Predicate []PredicatePolicy
+	// Priority replaces the priorities of the policy if not nil.
+	Priority []PriorityPolicy
 }
 
 type SyncPolicy struct {
 	Name   string
 	Period metav1.Duration
+	// Query is an optional Go template of PromQL used to query this metric,
+	// in which .NodeIP, .NodeName and .Labels of the node are available.
+	// The metric is queried as a percentage recording rule by node IP or name if not set.
+	Query string
+	// Scale is multiplied by the result of Query to get the usage ratio, which is 1 if not set.
+	// It must not be set without Query.
+	Scale float64
+	// Prediction makes the metric the predicted peak usage in the near future,
+	// which is fitted from the history of Query or Prediction.Metric.
+	Prediction *PredictionPolicy
 }
 
+// PredictionPolicy predicts the peak usage of a metric from its history queried
+// by range. It is only supported by the Prometheus metrics provider.
+type PredictionPolicy struct {
+	// Metric is the percentage recording rule whose history is queried by node IP
+	// or name, used only if Query of the sync policy is not set.
+	Metric string
+	// Model is the model fitted to the history, which is Linear if not set.
+	Model PredictionModel
+	// Lookback is the time range of the history, which is 24h if not set.
+	Lookback metav1.Duration
+	// Step is the resolution of the history and the prediction, which is 5m if not set.
+	Step metav1.Duration
+	// Horizon is the time range in the future whose peak usage is predicted.
+	Horizon metav1.Duration
+	// Season is the period of the Seasonal model, which is 24h if not set.
+	Season metav1.Duration
+}
+
+// PredictionModel is the model to predict usage from its history.
+type PredictionModel string
+
+const (
+	// PredictionModelLinear fits the history by linear regression, and extrapolates the trend.
+	PredictionModelLinear PredictionModel = "Linear"
+	// PredictionModelSeasonal repeats the history one season ago, shifted by the change
+	// of usage since then, to foresee daily peaks.
+	PredictionModelSeasonal PredictionModel = "Seasonal"
+)
+
 type PredicatePolicy struct {
 	Name           string
 	MaxLimitPecent float64
+	// MissingDataAction is the action taken when the metric of a node is missing,
+	// malformed or expired, which is Ignore if not set.
+	MissingDataAction MissingDataAction
+	// Resource is the resource measured by the metric, either cpu or memory, whose
+	// load of the pod is added to the usage if IncomingPodLoad is set.
+	Resource string
 }
 
 type PriorityPolicy struct {
 	Name   string
 	Weight float64
+	// MissingDataAction is the action taken when the metric of a node is missing,
+	// malformed or expired, which is PenalizeScore if not set.
+	MissingDataAction MissingDataAction
+	// Resource is the resource measured by the metric, either cpu or memory, whose
+	// requests of the pod scale the weight if PriorityWeighting is PodRequests.
+	Resource string
+}
+
+// MissingDataAction is the action taken when the load data of a node is not available.
+type MissingDataAction string
+
+const (
+	// MissingDataIgnore lets the node pass the predicate, or excludes the priority
+	// from the weighted average of scores.
+	MissingDataIgnore MissingDataAction = "Ignore"
+	// MissingDataReject filters out the node by the predicate, or gives the node
+	// the min score by the priority.
+	MissingDataReject MissingDataAction = "Reject"
+	// MissingDataPenalizeScore lets the node pass the predicate but gives it the min
+	// score, or regards the metric of the priority as fully used.
+	MissingDataPenalizeScore MissingDataAction = "PenalizeScore"
+	// MissingDataUseLastKnown uses the last known value of expired data, and falls
+	// back to the default action if there is no value at all.
+	MissingDataUseLastKnown MissingDataAction = "UseLastKnown"
+)
+
+// ScoreNormalization is the mode to normalize node scores across candidate nodes.
+type ScoreNormalization string
+
+const (
+	// ScoreNormalizationNone keeps node scores as they are.
+	ScoreNormalizationNone ScoreNormalization = "None"
+	// ScoreNormalizationMinMax scales node scores linearly, so that the least loaded
+	// node gets the max score and the most loaded one gets the min score.
+	ScoreNormalizationMinMax ScoreNormalization = "MinMax"
+	// ScoreNormalizationRank spreads node scores evenly by their ranks, and nodes with
+	// the same score share the same rank.
+	ScoreNormalizationRank ScoreNormalization = "Rank"
+)
+
+// PriorityWeighting is the mode to decide weights of priorities for each pod.
+type PriorityWeighting string
+
+const (
+	// PriorityWeightingStatic uses weights of priorities as they are.
+	PriorityWeightingStatic PriorityWeighting = "Static"
+	// PriorityWeightingPodRequests scales the weight of each priority with a resource
+	// by the share of the pod's requests of that resource in the node allocatable,
+	// relative to other resources, so that nodes are balanced in the dimension the
+	// pod stresses most.
+	PriorityWeightingPodRequests PriorityWeighting = "PodRequests"
+)
+
+// AnticipatedLoadPolicy estimates the load of pods bound within the sync period of
+// metrics from their requests.
+type AnticipatedLoadPolicy struct {
+	// Metrics are the metrics whose usage is increased by requests of pods bound recently.
+	Metrics []AnticipatedMetric
+	// RequestRatio is multiplied by requests of pods to estimate their usage,
+	// which is 1 if not set.
+	RequestRatio float64
+}
+
+// IncomingPodLoadPolicy estimates the load of the pod being scheduled from its
+// requests or limits.
+type IncomingPodLoadPolicy struct {
+	// LimitRatio estimates the load of the pod as this fraction of its limits
+	// instead of its requests if greater than 0. Requests are still used for
+	// resources without limits.
+	LimitRatio float64
+}
+
+// PostFilterPolicy decides how pods rejected by all nodes for their load are handled.
+type PostFilterPolicy struct {
+	// Candidates is the number of the least loaded nodes reported, which is 3 if not set.
+	Candidates int
+	// NominationTolerance is how much the usage of the least loaded node may exceed
+	// thresholds for it to be nominated. The pod then passes Filter on the nominated
+	// node with thresholds raised by this. Nodes are not nominated if not set.
+	NominationTolerance float64
+}
+
+// SafetyValvePolicy decides when Filter is degraded to score-only mode.
+type SafetyValvePolicy struct {
+	// MaxOverloadedRatio is the max ratio of nodes overloaded by predicates in the
+	// cluster. Nodes are not filtered by load while the ratio is exceeded.
+	MaxOverloadedRatio float64
+}
+
+// AnticipatedMetric maps a metric to the resource requested by pods.
+type AnticipatedMetric struct {
+	Name string
+	// Resource is the resource measured by the metric, either cpu or memory.
+	Resource string
 }
 
 type HotValuePolicy struct {
 	TimeRange metav1.Duration
 	Count     int
+	// Aggregation is how bindings within TimeRange are turned into the hot value,
+	// which is Floor if not set.
+	Aggregation HotValueAggregation
+	// PenaltyFactor is the score deducted per Count bindings within TimeRange, which is
+	// DefaultHotValuePenaltyFactor if not set. Zero deducts nothing.
+	PenaltyFactor *float64
+	// MaxPenalty caps the score deducted for this hot value, which is not capped if not set.
+	MaxPenalty float64
 }
+
+// HotValueAggregation is the function to turn recent bindings of a node into the hot value.
+type HotValueAggregation string
+
+const (
+	// HotValueAggregationFloor divides the number of bindings by Count and rounds it down.
+	HotValueAggregationFloor HotValueAggregation = "Floor"
+	// HotValueAggregationFraction divides the number of bindings by Count.
+	HotValueAggregationFraction HotValueAggregation = "Fraction"
+	// HotValueAggregationExponentialDecay weights each binding by exp(-age/TimeRange)
+	// before dividing by Count, so that recent bindings count more.
+	HotValueAggregationExponentialDecay HotValueAggregation = "ExponentialDecay"
+)
+
+// DefaultHotValuePenaltyFactor is the score deducted per Count bindings within
+// TimeRange if PenaltyFactor is not set.
+const DefaultHotValuePenaltyFactor = 10
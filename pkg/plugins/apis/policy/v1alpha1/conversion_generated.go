@@ -9,6 +9,7 @@ import (
 	unsafe "unsafe"
 
 	policy "github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -20,6 +21,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AnticipatedLoadPolicy)(nil), (*policy.AnticipatedLoadPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AnticipatedLoadPolicy_To_policy_AnticipatedLoadPolicy(a.(*AnticipatedLoadPolicy), b.(*policy.AnticipatedLoadPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.AnticipatedLoadPolicy)(nil), (*AnticipatedLoadPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_AnticipatedLoadPolicy_To_v1alpha1_AnticipatedLoadPolicy(a.(*policy.AnticipatedLoadPolicy), b.(*AnticipatedLoadPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AnticipatedMetric)(nil), (*policy.AnticipatedMetric)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AnticipatedMetric_To_policy_AnticipatedMetric(a.(*AnticipatedMetric), b.(*policy.AnticipatedMetric), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.AnticipatedMetric)(nil), (*AnticipatedMetric)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_AnticipatedMetric_To_v1alpha1_AnticipatedMetric(a.(*policy.AnticipatedMetric), b.(*AnticipatedMetric), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DynamicSchedulerPolicy)(nil), (*policy.DynamicSchedulerPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_DynamicSchedulerPolicy_To_policy_DynamicSchedulerPolicy(a.(*DynamicSchedulerPolicy), b.(*policy.DynamicSchedulerPolicy), scope)
 	}); err != nil {
@@ -40,6 +61,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*IncomingPodLoadPolicy)(nil), (*policy.IncomingPodLoadPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_IncomingPodLoadPolicy_To_policy_IncomingPodLoadPolicy(a.(*IncomingPodLoadPolicy), b.(*policy.IncomingPodLoadPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.IncomingPodLoadPolicy)(nil), (*IncomingPodLoadPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_IncomingPodLoadPolicy_To_v1alpha1_IncomingPodLoadPolicy(a.(*policy.IncomingPodLoadPolicy), b.(*IncomingPodLoadPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodePoolPolicy)(nil), (*policy.NodePoolPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodePoolPolicy_To_policy_NodePoolPolicy(a.(*NodePoolPolicy), b.(*policy.NodePoolPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.NodePoolPolicy)(nil), (*NodePoolPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_NodePoolPolicy_To_v1alpha1_NodePoolPolicy(a.(*policy.NodePoolPolicy), b.(*NodePoolPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PolicyOverride)(nil), (*policy.PolicyOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PolicyOverride_To_policy_PolicyOverride(a.(*PolicyOverride), b.(*policy.PolicyOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.PolicyOverride)(nil), (*PolicyOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_PolicyOverride_To_v1alpha1_PolicyOverride(a.(*policy.PolicyOverride), b.(*PolicyOverride), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PolicySpec)(nil), (*policy.PolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PolicySpec_To_policy_PolicySpec(a.(*PolicySpec), b.(*policy.PolicySpec), scope)
 	}); err != nil {
@@ -50,6 +101,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PostFilterPolicy)(nil), (*policy.PostFilterPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PostFilterPolicy_To_policy_PostFilterPolicy(a.(*PostFilterPolicy), b.(*policy.PostFilterPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.PostFilterPolicy)(nil), (*PostFilterPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_PostFilterPolicy_To_v1alpha1_PostFilterPolicy(a.(*policy.PostFilterPolicy), b.(*PostFilterPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PredicatePolicy)(nil), (*policy.PredicatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PredicatePolicy_To_policy_PredicatePolicy(a.(*PredicatePolicy), b.(*policy.PredicatePolicy), scope)
 	}); err != nil {
@@ -60,6 +121,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PredictionPolicy)(nil), (*policy.PredictionPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PredictionPolicy_To_policy_PredictionPolicy(a.(*PredictionPolicy), b.(*policy.PredictionPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.PredictionPolicy)(nil), (*PredictionPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_PredictionPolicy_To_v1alpha1_PredictionPolicy(a.(*policy.PredictionPolicy), b.(*PredictionPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PriorityPolicy)(nil), (*policy.PriorityPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PriorityPolicy_To_policy_PriorityPolicy(a.(*PriorityPolicy), b.(*policy.PriorityPolicy), scope)
 	}); err != nil {
@@ -70,6 +141,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SafetyValvePolicy)(nil), (*policy.SafetyValvePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SafetyValvePolicy_To_policy_SafetyValvePolicy(a.(*SafetyValvePolicy), b.(*policy.SafetyValvePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*policy.SafetyValvePolicy)(nil), (*SafetyValvePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_policy_SafetyValvePolicy_To_v1alpha1_SafetyValvePolicy(a.(*policy.SafetyValvePolicy), b.(*SafetyValvePolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SyncPolicy)(nil), (*policy.SyncPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SyncPolicy_To_policy_SyncPolicy(a.(*SyncPolicy), b.(*policy.SyncPolicy), scope)
 	}); err != nil {
@@ -83,6 +164,50 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha1_AnticipatedLoadPolicy_To_policy_AnticipatedLoadPolicy(in *AnticipatedLoadPolicy, out *policy.AnticipatedLoadPolicy, s conversion.Scope) error {
+	out.Metrics = *(*[]policy.AnticipatedMetric)(unsafe.Pointer(&in.Metrics))
+	out.RequestRatio = in.RequestRatio
+	return nil
+}
+
+// Convert_v1alpha1_AnticipatedLoadPolicy_To_policy_AnticipatedLoadPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_AnticipatedLoadPolicy_To_policy_AnticipatedLoadPolicy(in *AnticipatedLoadPolicy, out *policy.AnticipatedLoadPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AnticipatedLoadPolicy_To_policy_AnticipatedLoadPolicy(in, out, s)
+}
+
+func autoConvert_policy_AnticipatedLoadPolicy_To_v1alpha1_AnticipatedLoadPolicy(in *policy.AnticipatedLoadPolicy, out *AnticipatedLoadPolicy, s conversion.Scope) error {
+	out.Metrics = *(*[]AnticipatedMetric)(unsafe.Pointer(&in.Metrics))
+	out.RequestRatio = in.RequestRatio
+	return nil
+}
+
+// Convert_policy_AnticipatedLoadPolicy_To_v1alpha1_AnticipatedLoadPolicy is an autogenerated conversion function.
+func Convert_policy_AnticipatedLoadPolicy_To_v1alpha1_AnticipatedLoadPolicy(in *policy.AnticipatedLoadPolicy, out *AnticipatedLoadPolicy, s conversion.Scope) error {
+	return autoConvert_policy_AnticipatedLoadPolicy_To_v1alpha1_AnticipatedLoadPolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_AnticipatedMetric_To_policy_AnticipatedMetric(in *AnticipatedMetric, out *policy.AnticipatedMetric, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Resource = in.Resource
+	return nil
+}
+
+// Convert_v1alpha1_AnticipatedMetric_To_policy_AnticipatedMetric is an autogenerated conversion function.
+func Convert_v1alpha1_AnticipatedMetric_To_policy_AnticipatedMetric(in *AnticipatedMetric, out *policy.AnticipatedMetric, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AnticipatedMetric_To_policy_AnticipatedMetric(in, out, s)
+}
+
+func autoConvert_policy_AnticipatedMetric_To_v1alpha1_AnticipatedMetric(in *policy.AnticipatedMetric, out *AnticipatedMetric, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Resource = in.Resource
+	return nil
+}
+
+// Convert_policy_AnticipatedMetric_To_v1alpha1_AnticipatedMetric is an autogenerated conversion function.
+func Convert_policy_AnticipatedMetric_To_v1alpha1_AnticipatedMetric(in *policy.AnticipatedMetric, out *AnticipatedMetric, s conversion.Scope) error {
+	return autoConvert_policy_AnticipatedMetric_To_v1alpha1_AnticipatedMetric(in, out, s)
+}
+
 func autoConvert_v1alpha1_DynamicSchedulerPolicy_To_policy_DynamicSchedulerPolicy(in *DynamicSchedulerPolicy, out *policy.DynamicSchedulerPolicy, s conversion.Scope) error {
 	if err := Convert_v1alpha1_PolicySpec_To_policy_PolicySpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
@@ -110,6 +235,9 @@ func Convert_policy_DynamicSchedulerPolicy_To_v1alpha1_DynamicSchedulerPolicy(in
 func autoConvert_v1alpha1_HotValuePolicy_To_policy_HotValuePolicy(in *HotValuePolicy, out *policy.HotValuePolicy, s conversion.Scope) error {
 	out.TimeRange = in.TimeRange
 	out.Count = in.Count
+	out.Aggregation = policy.HotValueAggregation(in.Aggregation)
+	out.PenaltyFactor = (*float64)(unsafe.Pointer(in.PenaltyFactor))
+	out.MaxPenalty = in.MaxPenalty
 	return nil
 }
 
@@ -121,6 +249,9 @@ func Convert_v1alpha1_HotValuePolicy_To_policy_HotValuePolicy(in *HotValuePolicy
 func autoConvert_policy_HotValuePolicy_To_v1alpha1_HotValuePolicy(in *policy.HotValuePolicy, out *HotValuePolicy, s conversion.Scope) error {
 	out.TimeRange = in.TimeRange
 	out.Count = in.Count
+	out.Aggregation = HotValueAggregation(in.Aggregation)
+	out.PenaltyFactor = (*float64)(unsafe.Pointer(in.PenaltyFactor))
+	out.MaxPenalty = in.MaxPenalty
 	return nil
 }
 
@@ -129,11 +260,97 @@ func Convert_policy_HotValuePolicy_To_v1alpha1_HotValuePolicy(in *policy.HotValu
 	return autoConvert_policy_HotValuePolicy_To_v1alpha1_HotValuePolicy(in, out, s)
 }
 
+func autoConvert_v1alpha1_IncomingPodLoadPolicy_To_policy_IncomingPodLoadPolicy(in *IncomingPodLoadPolicy, out *policy.IncomingPodLoadPolicy, s conversion.Scope) error {
+	out.LimitRatio = in.LimitRatio
+	return nil
+}
+
+// Convert_v1alpha1_IncomingPodLoadPolicy_To_policy_IncomingPodLoadPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_IncomingPodLoadPolicy_To_policy_IncomingPodLoadPolicy(in *IncomingPodLoadPolicy, out *policy.IncomingPodLoadPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_IncomingPodLoadPolicy_To_policy_IncomingPodLoadPolicy(in, out, s)
+}
+
+func autoConvert_policy_IncomingPodLoadPolicy_To_v1alpha1_IncomingPodLoadPolicy(in *policy.IncomingPodLoadPolicy, out *IncomingPodLoadPolicy, s conversion.Scope) error {
+	out.LimitRatio = in.LimitRatio
+	return nil
+}
+
+// Convert_policy_IncomingPodLoadPolicy_To_v1alpha1_IncomingPodLoadPolicy is an autogenerated conversion function.
+func Convert_policy_IncomingPodLoadPolicy_To_v1alpha1_IncomingPodLoadPolicy(in *policy.IncomingPodLoadPolicy, out *IncomingPodLoadPolicy, s conversion.Scope) error {
+	return autoConvert_policy_IncomingPodLoadPolicy_To_v1alpha1_IncomingPodLoadPolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodePoolPolicy_To_policy_NodePoolPolicy(in *NodePoolPolicy, out *policy.NodePoolPolicy, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.SyncPeriod = *(*[]policy.SyncPolicy)(unsafe.Pointer(&in.SyncPeriod))
+	out.Predicate = *(*[]policy.PredicatePolicy)(unsafe.Pointer(&in.Predicate))
+	out.Priority = *(*[]policy.PriorityPolicy)(unsafe.Pointer(&in.Priority))
+	return nil
+}
+
+// Convert_v1alpha1_NodePoolPolicy_To_policy_NodePoolPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_NodePoolPolicy_To_policy_NodePoolPolicy(in *NodePoolPolicy, out *policy.NodePoolPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodePoolPolicy_To_policy_NodePoolPolicy(in, out, s)
+}
+
+func autoConvert_policy_NodePoolPolicy_To_v1alpha1_NodePoolPolicy(in *policy.NodePoolPolicy, out *NodePoolPolicy, s conversion.Scope) error {
+	out.Name = in.Name
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.SyncPeriod = *(*[]SyncPolicy)(unsafe.Pointer(&in.SyncPeriod))
+	out.Predicate = *(*[]PredicatePolicy)(unsafe.Pointer(&in.Predicate))
+	out.Priority = *(*[]PriorityPolicy)(unsafe.Pointer(&in.Priority))
+	return nil
+}
+
+// Convert_policy_NodePoolPolicy_To_v1alpha1_NodePoolPolicy is an autogenerated conversion function.
+func Convert_policy_NodePoolPolicy_To_v1alpha1_NodePoolPolicy(in *policy.NodePoolPolicy, out *NodePoolPolicy, s conversion.Scope) error {
+	return autoConvert_policy_NodePoolPolicy_To_v1alpha1_NodePoolPolicy(in, out, s)
+}
+
+func autoConvert_v1alpha1_PolicyOverride_To_policy_PolicyOverride(in *PolicyOverride, out *policy.PolicyOverride, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	out.IgnoreLoad = in.IgnoreLoad
+	out.Predicate = *(*[]policy.PredicatePolicy)(unsafe.Pointer(&in.Predicate))
+	out.Priority = *(*[]policy.PriorityPolicy)(unsafe.Pointer(&in.Priority))
+	return nil
+}
+
+// Convert_v1alpha1_PolicyOverride_To_policy_PolicyOverride is an autogenerated conversion function.
+func Convert_v1alpha1_PolicyOverride_To_policy_PolicyOverride(in *PolicyOverride, out *policy.PolicyOverride, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PolicyOverride_To_policy_PolicyOverride(in, out, s)
+}
+
+func autoConvert_policy_PolicyOverride_To_v1alpha1_PolicyOverride(in *policy.PolicyOverride, out *PolicyOverride, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PodSelector = (*v1.LabelSelector)(unsafe.Pointer(in.PodSelector))
+	out.IgnoreLoad = in.IgnoreLoad
+	out.Predicate = *(*[]PredicatePolicy)(unsafe.Pointer(&in.Predicate))
+	out.Priority = *(*[]PriorityPolicy)(unsafe.Pointer(&in.Priority))
+	return nil
+}
+
+// Convert_policy_PolicyOverride_To_v1alpha1_PolicyOverride is an autogenerated conversion function.
+func Convert_policy_PolicyOverride_To_v1alpha1_PolicyOverride(in *policy.PolicyOverride, out *PolicyOverride, s conversion.Scope) error {
+	return autoConvert_policy_PolicyOverride_To_v1alpha1_PolicyOverride(in, out, s)
+}
+
 func autoConvert_v1alpha1_PolicySpec_To_policy_PolicySpec(in *PolicySpec, out *policy.PolicySpec, s conversion.Scope) error {
 	out.SyncPeriod = *(*[]policy.SyncPolicy)(unsafe.Pointer(&in.SyncPeriod))
 	out.Predicate = *(*[]policy.PredicatePolicy)(unsafe.Pointer(&in.Predicate))
 	out.Priority = *(*[]policy.PriorityPolicy)(unsafe.Pointer(&in.Priority))
 	out.HotValue = *(*[]policy.HotValuePolicy)(unsafe.Pointer(&in.HotValue))
+	out.ScoreNormalization = policy.ScoreNormalization(in.ScoreNormalization)
+	out.PriorityWeighting = policy.PriorityWeighting(in.PriorityWeighting)
+	out.AnticipatedLoad = (*policy.AnticipatedLoadPolicy)(unsafe.Pointer(in.AnticipatedLoad))
+	out.IncomingPodLoad = (*policy.IncomingPodLoadPolicy)(unsafe.Pointer(in.IncomingPodLoad))
+	out.PostFilter = (*policy.PostFilterPolicy)(unsafe.Pointer(in.PostFilter))
+	out.SafetyValve = (*policy.SafetyValvePolicy)(unsafe.Pointer(in.SafetyValve))
+	out.Overrides = *(*[]policy.PolicyOverride)(unsafe.Pointer(&in.Overrides))
+	out.NodePools = *(*[]policy.NodePoolPolicy)(unsafe.Pointer(&in.NodePools))
 	return nil
 }
 
@@ -147,6 +364,14 @@ func autoConvert_policy_PolicySpec_To_v1alpha1_PolicySpec(in *policy.PolicySpec,
 	out.Predicate = *(*[]PredicatePolicy)(unsafe.Pointer(&in.Predicate))
 	out.Priority = *(*[]PriorityPolicy)(unsafe.Pointer(&in.Priority))
 	out.HotValue = *(*[]HotValuePolicy)(unsafe.Pointer(&in.HotValue))
+	out.ScoreNormalization = ScoreNormalization(in.ScoreNormalization)
+	out.PriorityWeighting = PriorityWeighting(in.PriorityWeighting)
+	out.AnticipatedLoad = (*AnticipatedLoadPolicy)(unsafe.Pointer(in.AnticipatedLoad))
+	out.IncomingPodLoad = (*IncomingPodLoadPolicy)(unsafe.Pointer(in.IncomingPodLoad))
+	out.PostFilter = (*PostFilterPolicy)(unsafe.Pointer(in.PostFilter))
+	out.SafetyValve = (*SafetyValvePolicy)(unsafe.Pointer(in.SafetyValve))
+	out.Overrides = *(*[]PolicyOverride)(unsafe.Pointer(&in.Overrides))
+	out.NodePools = *(*[]NodePoolPolicy)(unsafe.Pointer(&in.NodePools))
 	return nil
 }
 
@@ -155,9 +380,33 @@ func Convert_policy_PolicySpec_To_v1alpha1_PolicySpec(in *policy.PolicySpec, out
 	return autoConvert_policy_PolicySpec_To_v1alpha1_PolicySpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_PostFilterPolicy_To_policy_PostFilterPolicy(in *PostFilterPolicy, out *policy.PostFilterPolicy, s conversion.Scope) error {
+	out.Candidates = in.Candidates
+	out.NominationTolerance = in.NominationTolerance
+	return nil
+}
+
+// Convert_v1alpha1_PostFilterPolicy_To_policy_PostFilterPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_PostFilterPolicy_To_policy_PostFilterPolicy(in *PostFilterPolicy, out *policy.PostFilterPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PostFilterPolicy_To_policy_PostFilterPolicy(in, out, s)
+}
+
+func autoConvert_policy_PostFilterPolicy_To_v1alpha1_PostFilterPolicy(in *policy.PostFilterPolicy, out *PostFilterPolicy, s conversion.Scope) error {
+	out.Candidates = in.Candidates
+	out.NominationTolerance = in.NominationTolerance
+	return nil
+}
+
+// Convert_policy_PostFilterPolicy_To_v1alpha1_PostFilterPolicy is an autogenerated conversion function.
+func Convert_policy_PostFilterPolicy_To_v1alpha1_PostFilterPolicy(in *policy.PostFilterPolicy, out *PostFilterPolicy, s conversion.Scope) error {
+	return autoConvert_policy_PostFilterPolicy_To_v1alpha1_PostFilterPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_PredicatePolicy_To_policy_PredicatePolicy(in *PredicatePolicy, out *policy.PredicatePolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.MaxLimitPecent = in.MaxLimitPecent
+	out.MissingDataAction = policy.MissingDataAction(in.MissingDataAction)
+	out.Resource = in.Resource
 	return nil
 }
 
@@ -169,6 +418,8 @@ func Convert_v1alpha1_PredicatePolicy_To_policy_PredicatePolicy(in *PredicatePol
 func autoConvert_policy_PredicatePolicy_To_v1alpha1_PredicatePolicy(in *policy.PredicatePolicy, out *PredicatePolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.MaxLimitPecent = in.MaxLimitPecent
+	out.MissingDataAction = MissingDataAction(in.MissingDataAction)
+	out.Resource = in.Resource
 	return nil
 }
 
@@ -177,9 +428,41 @@ func Convert_policy_PredicatePolicy_To_v1alpha1_PredicatePolicy(in *policy.Predi
 	return autoConvert_policy_PredicatePolicy_To_v1alpha1_PredicatePolicy(in, out, s)
 }
 
+func autoConvert_v1alpha1_PredictionPolicy_To_policy_PredictionPolicy(in *PredictionPolicy, out *policy.PredictionPolicy, s conversion.Scope) error {
+	out.Metric = in.Metric
+	out.Model = policy.PredictionModel(in.Model)
+	out.Lookback = in.Lookback
+	out.Step = in.Step
+	out.Horizon = in.Horizon
+	out.Season = in.Season
+	return nil
+}
+
+// Convert_v1alpha1_PredictionPolicy_To_policy_PredictionPolicy is an autogenerated conversion function.
+func Convert_v1alpha1_PredictionPolicy_To_policy_PredictionPolicy(in *PredictionPolicy, out *policy.PredictionPolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PredictionPolicy_To_policy_PredictionPolicy(in, out, s)
+}
+
+func autoConvert_policy_PredictionPolicy_To_v1alpha1_PredictionPolicy(in *policy.PredictionPolicy, out *PredictionPolicy, s conversion.Scope) error {
+	out.Metric = in.Metric
+	out.Model = PredictionModel(in.Model)
+	out.Lookback = in.Lookback
+	out.Step = in.Step
+	out.Horizon = in.Horizon
+	out.Season = in.Season
+	return nil
+}
+
+// Convert_policy_PredictionPolicy_To_v1alpha1_PredictionPolicy is an autogenerated conversion function.
+func Convert_policy_PredictionPolicy_To_v1alpha1_PredictionPolicy(in *policy.PredictionPolicy, out *PredictionPolicy, s conversion.Scope) error {
+	return autoConvert_policy_PredictionPolicy_To_v1alpha1_PredictionPolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_PriorityPolicy_To_policy_PriorityPolicy(in *PriorityPolicy, out *policy.PriorityPolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Weight = in.Weight
+	out.MissingDataAction = policy.MissingDataAction(in.MissingDataAction)
+	out.Resource = in.Resource
 	return nil
 }
 
@@ -191,6 +474,8 @@ func Convert_v1alpha1_PriorityPolicy_To_policy_PriorityPolicy(in *PriorityPolicy
 func autoConvert_policy_PriorityPolicy_To_v1alpha1_PriorityPolicy(in *policy.PriorityPolicy, out *PriorityPolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Weight = in.Weight
+	out.MissingDataAction = MissingDataAction(in.MissingDataAction)
+	out.Resource = in.Resource
 	return nil
 }
 
@@ -199,9 +484,32 @@ func Convert_policy_PriorityPolicy_To_v1alpha1_PriorityPolicy(in *policy.Priorit
 	return autoConvert_policy_PriorityPolicy_To_v1alpha1_PriorityPolicy(in, out, s)
 }
 
+func autoConvert_v1alpha1_SafetyValvePolicy_To_policy_SafetyValvePolicy(in *SafetyValvePolicy, out *policy.SafetyValvePolicy, s conversion.Scope) error {
+	out.MaxOverloadedRatio = in.MaxOverloadedRatio
+	return nil
+}
+
+// Convert_v1alpha1_SafetyValvePolicy_To_policy_SafetyValvePolicy is an autogenerated conversion function.
+func Convert_v1alpha1_SafetyValvePolicy_To_policy_SafetyValvePolicy(in *SafetyValvePolicy, out *policy.SafetyValvePolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SafetyValvePolicy_To_policy_SafetyValvePolicy(in, out, s)
+}
+
+func autoConvert_policy_SafetyValvePolicy_To_v1alpha1_SafetyValvePolicy(in *policy.SafetyValvePolicy, out *SafetyValvePolicy, s conversion.Scope) error {
+	out.MaxOverloadedRatio = in.MaxOverloadedRatio
+	return nil
+}
+
+// Convert_policy_SafetyValvePolicy_To_v1alpha1_SafetyValvePolicy is an autogenerated conversion function.
+func Convert_policy_SafetyValvePolicy_To_v1alpha1_SafetyValvePolicy(in *policy.SafetyValvePolicy, out *SafetyValvePolicy, s conversion.Scope) error {
+	return autoConvert_policy_SafetyValvePolicy_To_v1alpha1_SafetyValvePolicy(in, out, s)
+}
+
 func autoConvert_v1alpha1_SyncPolicy_To_policy_SyncPolicy(in *SyncPolicy, out *policy.SyncPolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Period = in.Period
+	out.Query = in.Query
+	out.Scale = in.Scale
+	out.Prediction = (*policy.PredictionPolicy)(unsafe.Pointer(in.Prediction))
 	return nil
 }
 
@@ -213,6 +521,9 @@ func Convert_v1alpha1_SyncPolicy_To_policy_SyncPolicy(in *SyncPolicy, out *polic
 func autoConvert_policy_SyncPolicy_To_v1alpha1_SyncPolicy(in *policy.SyncPolicy, out *SyncPolicy, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Period = in.Period
+	out.Query = in.Query
+	out.Scale = in.Scale
+	out.Prediction = (*PredictionPolicy)(unsafe.Pointer(in.Prediction))
 	return nil
 }
 
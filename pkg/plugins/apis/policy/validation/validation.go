@@ -0,0 +1,411 @@
+package validation
+
+import (
+	"reflect"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// ValidateDynamicSchedulerPolicy checks if the given DynamicSchedulerPolicy is legal,
+// and returns the list of errors with their field paths.
+func ValidateDynamicSchedulerPolicy(p *policy.DynamicSchedulerPolicy) field.ErrorList {
+	return validatePolicySpec(&p.Spec, field.NewPath("spec"))
+}
+
+func validatePolicySpec(spec *policy.PolicySpec, fldPath *field.Path) field.ErrorList {
+	allErrs, syncedMetrics := validateSyncPolicies(spec.SyncPeriod, fldPath.Child("syncPolicy"))
+
+	allErrs = append(allErrs, validatePredicatePolicies(spec.Predicate, syncedMetrics, fldPath.Child("predicate"))...)
+	allErrs = append(allErrs, validatePriorityPolicies(spec.Priority, syncedMetrics, fldPath.Child("priority"))...)
+	allErrs = append(allErrs, validateHotValuePolicies(spec.HotValue, fldPath.Child("hotValue"))...)
+	allErrs = append(allErrs, validateAnticipatedLoad(spec, fldPath.Child("anticipatedLoad"))...)
+	allErrs = append(allErrs, validateIncomingPodLoad(spec.IncomingPodLoad, fldPath.Child("incomingPodLoad"))...)
+	allErrs = append(allErrs, validatePostFilter(spec.PostFilter, fldPath.Child("postFilter"))...)
+	allErrs = append(allErrs, validateSafetyValve(spec.SafetyValve, fldPath.Child("safetyValve"))...)
+	allErrs = append(allErrs, validateScoreNormalization(spec.ScoreNormalization, fldPath.Child("scoreNormalization"))...)
+	allErrs = append(allErrs, validatePriorityWeighting(spec.PriorityWeighting, fldPath.Child("priorityWeighting"))...)
+	allErrs = append(allErrs, validatePolicyOverrides(spec.Overrides, getAllSyncedMetrics(spec), fldPath.Child("overrides"))...)
+	allErrs = append(allErrs, validateNodePools(spec, syncedMetrics, fldPath.Child("nodePools"))...)
+
+	return allErrs
+}
+
+// validateSyncPolicies validates sync policies and returns the names of metrics synced legally.
+func validateSyncPolicies(syncPolicies []policy.SyncPolicy, fldPath *field.Path) (field.ErrorList, sets.String) {
+	allErrs, names := field.ErrorList{}, sets.NewString()
+
+	for i, p := range syncPolicies {
+		idxPath := fldPath.Index(i)
+
+		if p.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "metric name must be specified"))
+		} else if names.Has(p.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), p.Name))
+		}
+
+		if p.Query != "" {
+			if _, err := template.New(p.Name).Parse(p.Query); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("query"), p.Query, err.Error()))
+			}
+		}
+
+		if p.Scale < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("scale"), p.Scale, "must be greater than or equal to 0"))
+		} else if p.Scale != 0 && p.Query == "" {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("scale"), p.Scale, "must not be set if query is not set"))
+		}
+
+		if p.Prediction != nil {
+			allErrs = append(allErrs, validatePrediction(p, idxPath.Child("prediction"))...)
+		}
+
+		if p.Period.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("period"), p.Period.Duration.String(), "must be greater than 0"))
+		} else if p.Name != "" {
+			names.Insert(p.Name)
+		}
+	}
+
+	return allErrs, names
+}
+
+var supportedPredictionModels = sets.NewString(
+	string(policy.PredictionModelLinear),
+	string(policy.PredictionModelSeasonal),
+)
+
+func validatePrediction(syncPolicy policy.SyncPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if syncPolicy.Query == "" && syncPolicy.Prediction.Metric == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("metric"), "metric must be specified if query is not set"))
+	}
+
+	p := policy.GetPredictionPolicyWithDefaults(syncPolicy.Prediction)
+
+	if !supportedPredictionModels.Has(string(p.Model)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("model"), p.Model, supportedPredictionModels.List()))
+	}
+
+	if p.Horizon.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("horizon"), p.Horizon.Duration.String(), "must be greater than 0"))
+	} else if p.Horizon.Duration < p.Step.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("horizon"), p.Horizon.Duration.String(), "must be greater than or equal to step"))
+	}
+
+	if p.Step.Duration <= 0 || p.Step.Duration >= p.Lookback.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("step"), p.Step.Duration.String(), "must be greater than 0 and less than lookback"))
+	}
+
+	if p.Model == policy.PredictionModelSeasonal && (p.Season.Duration <= 0 || p.Season.Duration > p.Lookback.Duration) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("season"), p.Season.Duration.String(), "must be greater than 0 and no more than lookback"))
+	}
+
+	return allErrs
+}
+
+// supportedMetricResources are resources requested by pods, which metrics can be mapped to.
+var supportedMetricResources = sets.NewString("cpu", "memory")
+
+func validatePredicatePolicies(predicates []policy.PredicatePolicy, syncedMetrics sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, p := range predicates {
+		idxPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateMetricName(p.Name, syncedMetrics, idxPath.Child("name"))...)
+
+		if p.MaxLimitPecent < 0 || p.MaxLimitPecent > 1 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("maxLimitPecent"), p.MaxLimitPecent, "must be in the range [0, 1]"))
+		}
+
+		if p.Resource != "" && !supportedMetricResources.Has(p.Resource) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("resource"), p.Resource, supportedMetricResources.List()))
+		}
+
+		allErrs = append(allErrs, validateMissingDataAction(p.MissingDataAction, idxPath.Child("missingDataAction"))...)
+	}
+
+	return allErrs
+}
+
+func validatePriorityPolicies(priorities []policy.PriorityPolicy, syncedMetrics sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var totalWeight float64
+	for i, p := range priorities {
+		idxPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateMetricName(p.Name, syncedMetrics, idxPath.Child("name"))...)
+		allErrs = append(allErrs, validateMissingDataAction(p.MissingDataAction, idxPath.Child("missingDataAction"))...)
+
+		if p.Resource != "" && !supportedMetricResources.Has(p.Resource) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("resource"), p.Resource, supportedMetricResources.List()))
+		}
+
+		if p.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), p.Weight, "must be greater than or equal to 0"))
+			continue
+		}
+		totalWeight += p.Weight
+	}
+
+	if len(priorities) > 0 && totalWeight <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, totalWeight, "the sum of weights must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+var supportedHotValueAggregations = sets.NewString(
+	string(policy.HotValueAggregationFloor),
+	string(policy.HotValueAggregationFraction),
+	string(policy.HotValueAggregationExponentialDecay),
+)
+
+func validateHotValuePolicies(hotValues []policy.HotValuePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, p := range hotValues {
+		idxPath := fldPath.Index(i)
+
+		if p.TimeRange.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("timeRange"), p.TimeRange.Duration.String(), "must be greater than 0"))
+		}
+
+		if p.Count <= 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("count"), p.Count, "must be greater than 0"))
+		}
+
+		if p.Aggregation != "" && !supportedHotValueAggregations.Has(string(p.Aggregation)) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("aggregation"), p.Aggregation, supportedHotValueAggregations.List()))
+		}
+
+		if p.PenaltyFactor != nil && *p.PenaltyFactor < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("penaltyFactor"), *p.PenaltyFactor, "must be greater than or equal to 0"))
+		}
+
+		if p.MaxPenalty < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("maxPenalty"), p.MaxPenalty, "must be greater than or equal to 0"))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePolicyOverrides validates policy overrides, which apply to nodes of all node
+// pools, so their metrics can be synced by the sync policies of any node pool.
+func validatePolicyOverrides(overrides []policy.PolicyOverride, syncedMetrics sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs, names := field.ErrorList{}, sets.NewString()
+
+	for i, o := range overrides {
+		idxPath := fldPath.Index(i)
+
+		if o.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "override name must be specified"))
+		} else if names.Has(o.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), o.Name))
+		}
+		names.Insert(o.Name)
+
+		if o.PodSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(o.PodSelector); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("podSelector"), o.PodSelector, err.Error()))
+			}
+		}
+
+		allErrs = append(allErrs, validatePredicatePolicies(o.Predicate, syncedMetrics, idxPath.Child("predicate"))...)
+		allErrs = append(allErrs, validatePriorityPolicies(o.Priority, syncedMetrics, idxPath.Child("priority"))...)
+	}
+
+	return allErrs
+}
+
+func validateNodePools(spec *policy.PolicySpec, syncedMetrics sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs, names := field.ErrorList{}, sets.NewString()
+
+	// the same metric is queried in the same way for all nodes.
+	queries := map[string]policy.SyncPolicy{}
+	for _, p := range spec.SyncPeriod {
+		queries[p.Name] = p
+	}
+
+	for i, pool := range spec.NodePools {
+		idxPath := fldPath.Index(i)
+
+		if pool.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "node pool name must be specified"))
+		} else if names.Has(pool.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), pool.Name))
+		}
+		names.Insert(pool.Name)
+
+		if pool.NodeSelector == nil {
+			allErrs = append(allErrs, field.Required(idxPath.Child("nodeSelector"), "node selector must be specified"))
+		} else if _, err := metav1.LabelSelectorAsSelector(pool.NodeSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("nodeSelector"), pool.NodeSelector, err.Error()))
+		}
+
+		poolSyncedMetrics := syncedMetrics
+		if pool.SyncPeriod != nil {
+			var errs field.ErrorList
+			errs, poolSyncedMetrics = validateSyncPolicies(pool.SyncPeriod, idxPath.Child("syncPolicy"))
+			allErrs = append(allErrs, errs...)
+
+			for j, p := range pool.SyncPeriod {
+				if q, ok := queries[p.Name]; ok && (q.Query != p.Query || q.Scale != p.Scale || !reflect.DeepEqual(q.Prediction, p.Prediction)) {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("syncPolicy").Index(j), p.Name, "query, scale and prediction must be the same as other sync policies of this metric"))
+					continue
+				}
+				queries[p.Name] = p
+			}
+		}
+
+		allErrs = append(allErrs, validatePredicatePolicies(pool.Predicate, poolSyncedMetrics, idxPath.Child("predicate"))...)
+		allErrs = append(allErrs, validatePriorityPolicies(pool.Priority, poolSyncedMetrics, idxPath.Child("priority"))...)
+	}
+
+	return allErrs
+}
+
+// getAllSyncedMetrics returns the names of metrics synced by either the global sync
+// policies or those of any node pool.
+func getAllSyncedMetrics(spec *policy.PolicySpec) sets.String {
+	syncedMetrics := sets.NewString()
+	for _, p := range policy.GetAllSyncPolicies(spec) {
+		syncedMetrics.Insert(p.Name)
+	}
+
+	return syncedMetrics
+}
+
+// validateMetricName checks that the metric referenced by predicate or priority is synced.
+func validateMetricName(name string, syncedMetrics sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if name == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "metric name must be specified"))
+	} else if !syncedMetrics.Has(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, "no syncPolicy is defined for this metric"))
+	}
+
+	return allErrs
+}
+
+var supportedMissingDataActions = sets.NewString(
+	string(policy.MissingDataIgnore),
+	string(policy.MissingDataReject),
+	string(policy.MissingDataPenalizeScore),
+	string(policy.MissingDataUseLastKnown),
+)
+
+func validateMissingDataAction(action policy.MissingDataAction, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if action != "" && !supportedMissingDataActions.Has(string(action)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, action, supportedMissingDataActions.List()))
+	}
+
+	return allErrs
+}
+
+// validateAnticipatedLoad validates the anticipated load, whose metrics can be synced
+// by either the global sync policies or those of any node pool.
+func validateAnticipatedLoad(spec *policy.PolicySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.AnticipatedLoad == nil {
+		return allErrs
+	}
+
+	syncedMetrics := getAllSyncedMetrics(spec)
+	for i, m := range spec.AnticipatedLoad.Metrics {
+		idxPath := fldPath.Child("metrics").Index(i)
+
+		allErrs = append(allErrs, validateMetricName(m.Name, syncedMetrics, idxPath.Child("name"))...)
+
+		if !supportedMetricResources.Has(m.Resource) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("resource"), m.Resource, supportedMetricResources.List()))
+		}
+	}
+
+	if spec.AnticipatedLoad.RequestRatio < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestRatio"), spec.AnticipatedLoad.RequestRatio, "must be greater than or equal to 0"))
+	}
+
+	return allErrs
+}
+
+func validateIncomingPodLoad(incomingPodLoad *policy.IncomingPodLoadPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if incomingPodLoad != nil && (incomingPodLoad.LimitRatio < 0 || incomingPodLoad.LimitRatio > 1) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("limitRatio"), incomingPodLoad.LimitRatio, "must be in the range [0, 1]"))
+	}
+
+	return allErrs
+}
+
+func validatePostFilter(postFilter *policy.PostFilterPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if postFilter == nil {
+		return allErrs
+	}
+
+	if postFilter.Candidates < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("candidates"), postFilter.Candidates, "must be greater than or equal to 0"))
+	}
+
+	if postFilter.NominationTolerance < 0 || postFilter.NominationTolerance > 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nominationTolerance"), postFilter.NominationTolerance, "must be in the range [0, 1]"))
+	}
+
+	return allErrs
+}
+
+func validateSafetyValve(safetyValve *policy.SafetyValvePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if safetyValve != nil && (safetyValve.MaxOverloadedRatio <= 0 || safetyValve.MaxOverloadedRatio >= 1) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxOverloadedRatio"), safetyValve.MaxOverloadedRatio, "must be in the range (0, 1)"))
+	}
+
+	return allErrs
+}
+
+var supportedScoreNormalizations = sets.NewString(
+	string(policy.ScoreNormalizationNone),
+	string(policy.ScoreNormalizationMinMax),
+	string(policy.ScoreNormalizationRank),
+)
+
+func validateScoreNormalization(mode policy.ScoreNormalization, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if mode != "" && !supportedScoreNormalizations.Has(string(mode)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, mode, supportedScoreNormalizations.List()))
+	}
+
+	return allErrs
+}
+
+var supportedPriorityWeightings = sets.NewString(
+	string(policy.PriorityWeightingStatic),
+	string(policy.PriorityWeightingPodRequests),
+)
+
+func validatePriorityWeighting(mode policy.PriorityWeighting, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if mode != "" && !supportedPriorityWeightings.Has(string(mode)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, mode, supportedPriorityWeightings.List()))
+	}
+
+	return allErrs
+}
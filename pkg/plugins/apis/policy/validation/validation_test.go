@@ -0,0 +1,283 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+func newValidPolicy() *policy.DynamicSchedulerPolicy {
+	return &policy.DynamicSchedulerPolicy{
+		Spec: policy.PolicySpec{
+			SyncPeriod: []policy.SyncPolicy{
+				{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+				{Name: "mem_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+			},
+			Predicate: []policy.PredicatePolicy{
+				{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65},
+				{Name: "mem_usage_avg_5m", MaxLimitPecent: 0},
+			},
+			Priority: []policy.PriorityPolicy{
+				{Name: "cpu_usage_avg_5m", Weight: 0.2},
+				{Name: "mem_usage_avg_5m", Weight: 0.2},
+			},
+			HotValue: []policy.HotValuePolicy{
+				{TimeRange: metav1.Duration{Duration: 5 * time.Minute}, Count: 5},
+			},
+		},
+	}
+}
+
+func TestValidateDynamicSchedulerPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(p *policy.DynamicSchedulerPolicy)
+		wantPaths []string
+	}{
+		{
+			name:   "valid policy",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {},
+		},
+		{
+			name: "duplicated and zero period sync policies",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SyncPeriod = append(p.Spec.SyncPeriod,
+					policy.SyncPolicy{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: time.Minute}},
+					policy.SyncPolicy{Name: "cpu_usage_max_avg_1h"},
+				)
+			},
+			wantPaths: []string{"spec.syncPolicy[2].name", "spec.syncPolicy[3].period"},
+		},
+		{
+			name: "illegal query template and negative scale",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SyncPeriod[0].Query = `node_load1{instance="{{ .NodeIP }"}`
+				p.Spec.SyncPeriod[1].Query = `node_memory_usage{node="{{ .NodeName }}"}`
+				p.Spec.SyncPeriod[1].Scale = -1
+			},
+			wantPaths: []string{"spec.syncPolicy[0].query", "spec.syncPolicy[1].scale"},
+		},
+		{
+			name: "scale without query",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SyncPeriod[1].Scale = 0.01
+			},
+			wantPaths: []string{"spec.syncPolicy[1].scale"},
+		},
+		{
+			name: "negative maxLimitPecent and unsynced predicate",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.Predicate[0].MaxLimitPecent = -0.1
+				p.Spec.Predicate = append(p.Spec.Predicate, policy.PredicatePolicy{Name: "cpu_usage_max_avg_1h", MaxLimitPecent: 0.75})
+			},
+			wantPaths: []string{"spec.predicate[0].maxLimitPecent", "spec.predicate[2].name"},
+		},
+		{
+			name: "unsupported missing data actions",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.Predicate[0].MissingDataAction = policy.MissingDataReject
+				p.Spec.Predicate[1].MissingDataAction = "Drop"
+				p.Spec.Priority[0].MissingDataAction = "reject"
+			},
+			wantPaths: []string{"spec.predicate[1].missingDataAction", "spec.priority[0].missingDataAction"},
+		},
+		{
+			name: "weights sum to zero",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.Priority[0].Weight = 0
+				p.Spec.Priority[1].Weight = 0
+			},
+			wantPaths: []string{"spec.priority"},
+		},
+		{
+			name: "negative weight",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.Priority[1].Weight = -1
+			},
+			wantPaths: []string{"spec.priority[1].weight"},
+		},
+		{
+			name: "illegal overrides",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.Overrides = []policy.PolicyOverride{
+					{Name: "batch", Namespaces: []string{"batch"}, IgnoreLoad: true},
+					{
+						Name: "batch",
+						PodSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Equals"}},
+						},
+						Predicate: []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 1.5}},
+					},
+				}
+			},
+			wantPaths: []string{"spec.overrides[1].name", "spec.overrides[1].podSelector", "spec.overrides[1].predicate[0].maxLimitPecent"},
+		},
+		{
+			name: "override metric synced by node pool",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.NodePools = []policy.NodePoolPolicy{
+					{
+						Name:         "gpu",
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+						SyncPeriod:   []policy.SyncPolicy{{Name: "gpu_usage_avg_5m", Period: metav1.Duration{Duration: time.Minute}}},
+					},
+				}
+				p.Spec.Overrides = []policy.PolicyOverride{
+					{
+						Name:       "training",
+						Namespaces: []string{"training"},
+						Predicate: []policy.PredicatePolicy{
+							{Name: "gpu_usage_avg_5m", MaxLimitPecent: 0.8},
+							{Name: "disk_usage_avg_5m", MaxLimitPecent: 0.8},
+						},
+					},
+				}
+			},
+			wantPaths: []string{"spec.overrides[0].predicate[1].name"},
+		},
+		{
+			name: "illegal node pools",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.NodePools = []policy.NodePoolPolicy{
+					{
+						Name:         "gpu",
+						NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+						SyncPeriod: []policy.SyncPolicy{
+							{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: time.Minute}, Query: `node_cpu_usage{node="{{ .NodeName }}"}`},
+							{Name: "gpu_usage_avg_5m", Period: metav1.Duration{Duration: time.Minute}},
+						},
+						Predicate: []policy.PredicatePolicy{{Name: "mem_usage_avg_5m", MaxLimitPecent: 0.8}},
+					},
+					{Name: "highmem"},
+				}
+			},
+			wantPaths: []string{"spec.nodePools[0].syncPolicy[0]", "spec.nodePools[0].predicate[0].name", "spec.nodePools[1].nodeSelector"},
+		},
+		{
+			name: "zero hot value count",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.HotValue[0].Count = 0
+			},
+			wantPaths: []string{"spec.hotValue[0].count"},
+		},
+		{
+			name: "unsupported hot value aggregation and negative penalty",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.HotValue[0].Aggregation = "Ceil"
+				penaltyFactor := -1.0
+				p.Spec.HotValue[0].PenaltyFactor = &penaltyFactor
+				p.Spec.HotValue[0].MaxPenalty = -10
+			},
+			wantPaths: []string{"spec.hotValue[0].aggregation", "spec.hotValue[0].penaltyFactor", "spec.hotValue[0].maxPenalty"},
+		},
+		{
+			name: "illegal predictions",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SyncPeriod = append(p.Spec.SyncPeriod,
+					policy.SyncPolicy{
+						Name:       "cpu_usage_predicted",
+						Period:     metav1.Duration{Duration: 10 * time.Minute},
+						Prediction: &policy.PredictionPolicy{Model: "ARIMA"},
+					},
+					policy.SyncPolicy{
+						Name:   "mem_usage_predicted",
+						Period: metav1.Duration{Duration: 10 * time.Minute},
+						Prediction: &policy.PredictionPolicy{
+							Metric:   "mem_usage_active",
+							Model:    policy.PredictionModelSeasonal,
+							Lookback: metav1.Duration{Duration: time.Hour},
+							Horizon:  metav1.Duration{Duration: 30 * time.Minute},
+						},
+					})
+			},
+			wantPaths: []string{
+				"spec.syncPolicy[2].prediction.metric", "spec.syncPolicy[2].prediction.model", "spec.syncPolicy[2].prediction.horizon",
+				"spec.syncPolicy[3].prediction.season",
+			},
+		},
+		{
+			name: "prediction horizon shorter than step",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SyncPeriod = append(p.Spec.SyncPeriod, policy.SyncPolicy{
+					Name:   "cpu_usage_predicted",
+					Period: metav1.Duration{Duration: 10 * time.Minute},
+					Prediction: &policy.PredictionPolicy{
+						Metric:  "cpu_usage_active",
+						Horizon: metav1.Duration{Duration: time.Minute},
+					},
+				})
+			},
+			wantPaths: []string{"spec.syncPolicy[2].prediction.horizon"},
+		},
+		{
+			name: "illegal anticipated load",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.AnticipatedLoad = &policy.AnticipatedLoadPolicy{
+					Metrics: []policy.AnticipatedMetric{
+						{Name: "cpu_usage_avg_5m", Resource: "cpu"},
+						{Name: "gpu_usage_avg_5m", Resource: "gpu"},
+					},
+					RequestRatio: -1,
+				}
+			},
+			wantPaths: []string{"spec.anticipatedLoad.metrics[1].name", "spec.anticipatedLoad.metrics[1].resource", "spec.anticipatedLoad.requestRatio"},
+		},
+		{
+			name: "unsupported score normalization",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.ScoreNormalization = "ZScore"
+			},
+			wantPaths: []string{"spec.scoreNormalization"},
+		},
+		{
+			name: "illegal priority weighting",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.PriorityWeighting = "PodLimits"
+				p.Spec.Priority[0].Resource = "gpu"
+			},
+			wantPaths: []string{"spec.priority[0].resource", "spec.priorityWeighting"},
+		},
+		{
+			name: "illegal incoming pod load",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.IncomingPodLoad = &policy.IncomingPodLoadPolicy{LimitRatio: 1.5}
+				p.Spec.Predicate[0].Resource = "gpu"
+			},
+			wantPaths: []string{"spec.predicate[0].resource", "spec.incomingPodLoad.limitRatio"},
+		},
+		{
+			name: "illegal post filter",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.PostFilter = &policy.PostFilterPolicy{Candidates: -1, NominationTolerance: 2}
+			},
+			wantPaths: []string{"spec.postFilter.candidates", "spec.postFilter.nominationTolerance"},
+		},
+		{
+			name: "illegal safety valve",
+			mutate: func(p *policy.DynamicSchedulerPolicy) {
+				p.Spec.SafetyValve = &policy.SafetyValvePolicy{}
+			},
+			wantPaths: []string{"spec.safetyValve.maxOverloadedRatio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newValidPolicy()
+			tt.mutate(p)
+
+			errs := ValidateDynamicSchedulerPolicy(p)
+			if len(errs) != len(tt.wantPaths) {
+				t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(tt.wantPaths))
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantPaths[i] {
+					t.Errorf("error %d has field %q, want %q", i, err.Field, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package dynamic
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// getAnticipatedLoad estimates the usage of pods on the node which are bound within
+// the sync period of each metric, and so not reflected by the metric yet. It returns
+// the usage added to each metric.
+func getAnticipatedLoad(nodeInfo *framework.NodeInfo, policySpec policy.PolicySpec) map[string]float64 {
+	anticipatedLoad := policySpec.AnticipatedLoad
+	node := nodeInfo.Node()
+	if anticipatedLoad == nil || node == nil {
+		return nil
+	}
+
+	ratio := anticipatedLoad.RequestRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+
+	now, usage := time.Now(), map[string]float64{}
+
+	for _, m := range anticipatedLoad.Metrics {
+		period := getSyncPeriod(policySpec.SyncPeriod, m.Name)
+		if period == 0 {
+			continue
+		}
+
+		resourceName := v1.ResourceName(m.Resource)
+		allocatable, ok := node.Status.Allocatable[resourceName]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+
+		var requested int64
+		for _, podInfo := range nodeInfo.Pods {
+			if boundSince(podInfo.Pod, now.Add(-period)) {
+				request := resourcehelper.GetResourceRequestQuantity(podInfo.Pod, resourceName)
+				requested += request.MilliValue()
+			}
+		}
+
+		if requested > 0 {
+			usage[m.Name] = ratio * float64(requested) / float64(allocatable.MilliValue())
+		}
+	}
+
+	return usage
+}
+
+// getIncomingLoad estimates the usage of the pod being scheduled on the node, by the
+// name of predicates with a resource. It returns nil if IncomingPodLoad is not set.
+func getIncomingLoad(pod *v1.Pod, node *v1.Node, policySpec policy.PolicySpec) map[string]float64 {
+	incomingPodLoad := policySpec.IncomingPodLoad
+	if incomingPodLoad == nil {
+		return nil
+	}
+
+	requests, limits := resourcehelper.PodRequestsAndLimits(pod)
+	usage := map[string]float64{}
+
+	for _, p := range policySpec.Predicate {
+		if p.Resource == "" {
+			continue
+		}
+
+		resourceName := v1.ResourceName(p.Resource)
+		allocatable, ok := node.Status.Allocatable[resourceName]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+
+		load := float64(requests.Name(resourceName, resource.DecimalSI).MilliValue())
+		if limit, ok := limits[resourceName]; ok && incomingPodLoad.LimitRatio > 0 {
+			load = incomingPodLoad.LimitRatio * float64(limit.MilliValue())
+		}
+
+		if load > 0 {
+			usage[p.Name] = load / float64(allocatable.MilliValue())
+		}
+	}
+
+	return usage
+}
+
+// boundSince checks if the pod is bound to the node after the given time. Pods assumed
+// by the scheduler but not bound yet have no PodScheduled condition, and are judged by
+// their start time if any.
+func boundSince(pod *v1.Pod, since time.Time) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue {
+			return condition.LastTransitionTime.Time.After(since)
+		}
+	}
+
+	return pod.Status.StartTime == nil || pod.Status.StartTime.Time.After(since)
+}
+
+func getSyncPeriod(syncPeriodList []policy.SyncPolicy, name string) time.Duration {
+	for _, p := range syncPeriodList {
+		if p.Name == name {
+			return p.Period.Duration
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,110 @@
+package dynamic
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestGetAnticipatedLoad(t *testing.T) {
+	now := time.Now()
+
+	newPod := func(cpu string, scheduledAt *time.Time) *v1.Pod {
+		pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+		}}}}
+		if scheduledAt != nil {
+			pod.Status.Conditions = []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(*scheduledAt)},
+			}
+		}
+		return pod
+	}
+	recent, old := now.Add(-30*time.Second), now.Add(-10*time.Minute)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Annotations: map[string]string{"cpu_usage_avg_5m": "0.50000," + utils.FormatTimestamp(now, utils.RFC3339TimestampFormat)},
+		},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}},
+	}
+	nodeInfo := framework.NewNodeInfo(newPod("1", &recent), newPod("1", &old), newPod("500m", nil))
+	nodeInfo.SetNode(node)
+
+	policySpec := policy.PolicySpec{
+		SyncPeriod: []policy.SyncPolicy{{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}}},
+		AnticipatedLoad: &policy.AnticipatedLoadPolicy{
+			Metrics: []policy.AnticipatedMetric{{Name: "cpu_usage_avg_5m", Resource: "cpu"}},
+		},
+	}
+
+	// the recently bound pod and the assumed pod request 1.5 of 4 cpus.
+	anticipated := getAnticipatedLoad(nodeInfo, policySpec)
+	if anticipated["cpu_usage_avg_5m"] != 0.375 {
+		t.Fatalf("got anticipated usage %f, want 0.375", anticipated["cpu_usage_avg_5m"])
+	}
+
+	load := newNodeLoadCache().get(node)
+	load.anticipated = anticipated
+	e := explainPredicate(load, policy.PredicatePolicy{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.75}, 8*time.Minute)
+	if e.Usage != 0.875 || !e.Overload {
+		t.Errorf("got usage %f and overload %t, want 0.875 and true", e.Usage, e.Overload)
+	}
+}
+
+func TestGetIncomingLoad(t *testing.T) {
+	now := time.Now()
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Annotations: map[string]string{"cpu_usage_avg_5m": "0.64000," + utils.FormatTimestamp(now, utils.RFC3339TimestampFormat)},
+		},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}},
+	}
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+			Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		},
+	}}}}
+
+	predicate := policy.PredicatePolicy{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65, Resource: "cpu"}
+	policySpec := policy.PolicySpec{Predicate: []policy.PredicatePolicy{predicate}}
+
+	if incoming := getIncomingLoad(pod, node, policySpec); incoming != nil {
+		t.Fatalf("got incoming usage %v without IncomingPodLoad, want nil", incoming)
+	}
+
+	tests := []struct {
+		name       string
+		limitRatio float64
+		want       float64
+	}{
+		{name: "by requests", want: 0.25},
+		{name: "by a fraction of limits", limitRatio: 0.75, want: 0.375},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policySpec.IncomingPodLoad = &policy.IncomingPodLoadPolicy{LimitRatio: tt.limitRatio}
+
+			load := newNodeLoadCache().get(node)
+			load.incoming = getIncomingLoad(pod, node, policySpec)
+
+			// the node at 64% passes the threshold of 65% without the pod.
+			e := explainPredicate(load, predicate, 8*time.Minute)
+			if e.Incoming != tt.want || !e.Overload {
+				t.Errorf("got incoming usage %f and overload %t, want %f and true", e.Incoming, e.Overload, tt.want)
+			}
+		})
+	}
+}
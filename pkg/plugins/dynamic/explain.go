@@ -0,0 +1,232 @@
+package dynamic
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+// PredicateExplanation shows how a node is judged by one predicate policy.
+type PredicateExplanation struct {
+	Name      string  `json:"name"`
+	Usage     float64 `json:"usage"`
+	Threshold float64 `json:"threshold"`
+	Overload  bool    `json:"overload"`
+	// Anticipated is the estimated usage of pods bound recently, included in Usage.
+	Anticipated float64 `json:"anticipated,omitempty"`
+	// Incoming is the estimated usage of the pod being scheduled, included in Usage.
+	Incoming float64 `json:"incoming,omitempty"`
+	// Stale is true if the metric exists but is not updated within its active period.
+	Stale bool `json:"stale,omitempty"`
+	// Error is the reason why the usage is not available.
+	Error string `json:"error,omitempty"`
+	// MissingDataAction is the action taken if the usage is not available.
+	MissingDataAction policy.MissingDataAction `json:"missingDataAction,omitempty"`
+}
+
+// rejectReason returns the reason why the node is rejected by this predicate,
+// or an empty string if the node passes.
+func (e *PredicateExplanation) rejectReason(nodeName string) string {
+	if e.Overload {
+		return fmt.Sprintf("Load[%s] of node[%s] is too high", e.Name, nodeName)
+	}
+
+	if e.MissingDataAction == policy.MissingDataReject {
+		return fmt.Sprintf("Load[%s] of node[%s] is unavailable", e.Name, nodeName)
+	}
+
+	return ""
+}
+
+// PriorityExplanation shows how a node is scored by one priority policy.
+type PriorityExplanation struct {
+	Name   string  `json:"name"`
+	Usage  float64 `json:"usage"`
+	Weight float64 `json:"weight"`
+	// Anticipated is the estimated usage of pods bound recently, included in Usage.
+	Anticipated float64 `json:"anticipated,omitempty"`
+	// Score is the weighted score of this priority.
+	Score float64 `json:"score"`
+	// Contribution is the part of the node score coming from this priority.
+	Contribution float64 `json:"contribution"`
+	// Stale is true if the metric exists but is not updated within its active period.
+	Stale bool `json:"stale,omitempty"`
+	// Error is the reason why the usage is not available.
+	Error string `json:"error,omitempty"`
+	// MissingDataAction is the action taken if the usage is not available.
+	MissingDataAction policy.MissingDataAction `json:"missingDataAction,omitempty"`
+}
+
+// NodeExplanation shows how the Dynamic plugin filters and scores a node.
+type NodeExplanation struct {
+	NodeName string `json:"nodeName"`
+	// Override is the name of the policy override in effect for the pod.
+	Override string `json:"override,omitempty"`
+	// IgnoreLoad is true if the policy override ignores the load of nodes.
+	IgnoreLoad bool `json:"ignoreLoad,omitempty"`
+	// NodePool is the name of the node pool which the node belongs to.
+	NodePool string `json:"nodePool,omitempty"`
+	// Filtered is true if the node is rejected at the Filter stage.
+	Filtered   bool                   `json:"filtered"`
+	Reason     string                 `json:"reason,omitempty"`
+	Predicates []PredicateExplanation `json:"predicates"`
+	Priorities []PriorityExplanation  `json:"priorities"`
+	// Score is the weighted average of priority scores.
+	Score int `json:"score"`
+	// Penalized is true if the node gets the min score for its missing data.
+	Penalized bool `json:"penalized,omitempty"`
+	// HotValue is the hot value annotation of the node.
+	HotValue        float64 `json:"hotValue"`
+	HotValuePenalty int     `json:"hotValuePenalty"`
+	// EstimatedScore is the score deducted by the hot value penalty. It may differ
+	// from the final score of the running scheduler, which has the inputs listed
+	// in Unavailable as well.
+	EstimatedScore int64 `json:"estimatedScore"`
+	// NormalizedScore is the estimated score normalized across nodes not filtered,
+	// which is the same as EstimatedScore if scores are not normalized.
+	NormalizedScore int64 `json:"normalizedScore"`
+	// Unavailable lists the inputs of the policy only known by the running scheduler,
+	// which are not included in this explanation.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Inputs of Filter and Score only known by the running scheduler.
+const (
+	// UnavailableHotValue is the hot value of bindings made by the scheduler since
+	// the annotation is synced.
+	UnavailableHotValue = "hotValueOfRecentBindings"
+	// UnavailableAnticipatedLoad is the load anticipated for pods bound recently.
+	UnavailableAnticipatedLoad = "anticipatedLoad"
+	// UnavailableSafetyValve is whether Filter is degraded by the safety valve.
+	UnavailableSafetyValve = "safetyValve"
+	// UnavailableNominationTolerance is the tolerance of thresholds for pods
+	// nominated at PostFilter.
+	UnavailableNominationTolerance = "nominationTolerance"
+)
+
+// getUnavailableInputs returns the inputs of the policy only known by the running scheduler.
+func getUnavailableInputs(policySpec policy.PolicySpec) []string {
+	var inputs []string
+
+	if len(policySpec.HotValue) > 0 {
+		inputs = append(inputs, UnavailableHotValue)
+	}
+	if policySpec.AnticipatedLoad != nil {
+		inputs = append(inputs, UnavailableAnticipatedLoad)
+	}
+	if policySpec.SafetyValve != nil {
+		inputs = append(inputs, UnavailableSafetyValve)
+	}
+	if policySpec.PostFilter != nil {
+		inputs = append(inputs, UnavailableNominationTolerance)
+	}
+
+	return inputs
+}
+
+// Explain shows how the Dynamic plugin filters and scores each node for the pod
+// under the given policy, including the policy override chosen by the pod. Unlike
+// Filter, all predicates are evaluated instead of stopping at the first failure.
+// Only node annotations are read, so inputs kept in memory by the running scheduler
+// are listed as unavailable instead.
+func Explain(pod *v1.Pod, nodes []*v1.Node, policySpec policy.PolicySpec) []*NodeExplanation {
+	cache, policyState := newNodeLoadCache(), resolvePolicy(pod, policySpec, policy.NewNodePoolSelector(policySpec.NodePools), newOverrideSelector(policySpec.Overrides))
+
+	var explanations []*NodeExplanation
+	for _, node := range nodes {
+		if policyState.ignoreLoad {
+			explanations = append(explanations, &NodeExplanation{
+				NodeName:       node.Name,
+				Override:       policyState.override,
+				IgnoreLoad:     true,
+				EstimatedScore: framework.MinNodeScore,
+			})
+			continue
+		}
+
+		spec, nodePool := policyState.specOf(node)
+		spec.Priority = weighPriorities(pod, node, spec)
+
+		load := cache.get(node)
+		load.incoming = getIncomingLoad(pod, node, spec)
+
+		e := explainNode(pod, node.Name, load, spec)
+		e.Override, e.NodePool = policyState.override, nodePool
+		e.Unavailable = getUnavailableInputs(spec)
+		explanations = append(explanations, e)
+	}
+
+	normalizeExplanations(explanations, policySpec.ScoreNormalization)
+
+	return explanations
+}
+
+// normalizeExplanations normalizes estimated scores of nodes not filtered, just as
+// NormalizeScore does for candidate nodes.
+func normalizeExplanations(explanations []*NodeExplanation, mode policy.ScoreNormalization) {
+	var candidates []*NodeExplanation
+	var scores framework.NodeScoreList
+	for _, e := range explanations {
+		e.NormalizedScore = e.EstimatedScore
+		if !e.Filtered {
+			candidates = append(candidates, e)
+			scores = append(scores, framework.NodeScore{Name: e.NodeName, Score: e.EstimatedScore})
+		}
+	}
+
+	if len(candidates) == 0 || candidates[0].IgnoreLoad {
+		return
+	}
+
+	normalizeScores(scores, mode)
+	for i, e := range candidates {
+		e.NormalizedScore = scores[i].Score
+	}
+}
+
+func explainNode(pod *v1.Pod, nodeName string, load *nodeLoad, policySpec policy.PolicySpec) *NodeExplanation {
+	e := &NodeExplanation{NodeName: nodeName}
+
+	for _, predicatePolicy := range policySpec.Predicate {
+		activeDuration, err := policy.GetActiveDuration(policySpec.SyncPeriod, predicatePolicy.Name)
+		if err != nil || activeDuration == 0 {
+			e.Predicates = append(e.Predicates, PredicateExplanation{
+				Name:      predicatePolicy.Name,
+				Threshold: predicatePolicy.MaxLimitPecent,
+				Error:     fmt.Sprintf("failed to get active duration: %v", err),
+			})
+			continue
+		}
+
+		predicate := explainPredicate(load, predicatePolicy, activeDuration)
+		if reason := predicate.rejectReason(nodeName); reason != "" && !e.Filtered {
+			e.Filtered, e.Reason = true, reason
+		}
+		if predicate.MissingDataAction == policy.MissingDataPenalizeScore {
+			e.Penalized = true
+		}
+		e.Predicates = append(e.Predicates, predicate)
+	}
+
+	// daemonset pods are never filtered by the Dynamic plugin.
+	if e.Filtered && utils.IsDaemonsetPod(pod) {
+		e.Filtered, e.Reason = false, "daemonset pods are not filtered"
+	}
+
+	e.Priorities, e.Score = explainPriorities(load, policySpec)
+	for _, priority := range e.Priorities {
+		if priority.MissingDataAction == policy.MissingDataReject {
+			e.Penalized = true
+		}
+	}
+
+	e.HotValue = getNodeHotValue(nodeName, load)
+	e.HotValuePenalty = getHotValuePenalty(e.HotValue)
+	e.EstimatedScore = getFinalScore(e.Score, e.HotValuePenalty, e.Penalized)
+
+	return e
+}
@@ -0,0 +1,178 @@
+package dynamic
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestExplain(t *testing.T) {
+	now := time.Now()
+	fresh := utils.FormatTimestamp(now, utils.RFC3339TimestampFormat)
+	expired := utils.FormatTimestamp(now.Add(-time.Hour), utils.RFC3339TimestampFormat)
+
+	policySpec := policy.PolicySpec{
+		SyncPeriod: []policy.SyncPolicy{
+			{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+			{Name: "mem_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+		},
+		Predicate: []policy.PredicatePolicy{
+			{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65},
+			{Name: "mem_usage_avg_5m", MaxLimitPecent: 0.65},
+		},
+		Priority: []policy.PriorityPolicy{
+			{Name: "cpu_usage_avg_5m", Weight: 0.5},
+			{Name: "mem_usage_avg_5m", Weight: 0.5},
+		},
+		HotValue: []policy.HotValuePolicy{{TimeRange: metav1.Duration{Duration: time.Minute}, Count: 1}},
+	}
+
+	nodes := []*v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "busy",
+				Annotations: map[string]string{
+					"cpu_usage_avg_5m": "0.75000," + fresh,
+					"mem_usage_avg_5m": "0.75000," + fresh,
+					NodeHotValue:       "10," + fresh,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "stale",
+				Annotations: map[string]string{
+					"cpu_usage_avg_5m": "0.20000," + fresh,
+					"mem_usage_avg_5m": "0.90000," + expired,
+				},
+			},
+		},
+	}
+
+	explanations := Explain(&v1.Pod{}, nodes, policySpec)
+	if len(explanations) != 2 {
+		t.Fatalf("got %d explanations, want 2", len(explanations))
+	}
+
+	busy := explanations[0]
+	if !busy.Filtered || busy.Reason != "Load[cpu_usage_avg_5m] of node[busy] is too high" {
+		t.Errorf("busy node: got filtered %t with reason %q", busy.Filtered, busy.Reason)
+	}
+	if !busy.Predicates[0].Overload || !busy.Predicates[1].Overload {
+		t.Errorf("busy node: all predicates should be evaluated, got %+v", busy.Predicates)
+	}
+	// (0.25*0.5 + 0.25*0.5) * 100 = 25, minus 10 for hot value 10.
+	if busy.Score != 25 || busy.HotValuePenalty != 10 || busy.EstimatedScore != 15 {
+		t.Errorf("busy node: got score %d, penalty %d and estimated score %d", busy.Score, busy.HotValuePenalty, busy.EstimatedScore)
+	}
+	// bindings made by the scheduler since the annotation is synced are unknown.
+	if len(busy.Unavailable) != 1 || busy.Unavailable[0] != UnavailableHotValue {
+		t.Errorf("busy node: got unavailable inputs %v, want %s", busy.Unavailable, UnavailableHotValue)
+	}
+
+	stale := explanations[1]
+	if stale.Filtered {
+		t.Errorf("stale node should not be filtered: %s", stale.Reason)
+	}
+	if !stale.Predicates[1].Stale || !stale.Priorities[1].Stale {
+		t.Errorf("stale node: mem_usage_avg_5m should be stale, got %+v and %+v", stale.Predicates[1], stale.Priorities[1])
+	}
+	if stale.Score != 40 || stale.Priorities[0].Contribution != 40 {
+		t.Errorf("stale node: got score %d and cpu contribution %f", stale.Score, stale.Priorities[0].Contribution)
+	}
+}
+
+func TestExplainMissingDataAction(t *testing.T) {
+	expired := utils.FormatTimestamp(time.Now().Add(-time.Hour), utils.RFC3339TimestampFormat)
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				"cpu_usage_avg_5m": "0.75000," + expired,
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		predicateAction   policy.MissingDataAction
+		priorityAction    policy.MissingDataAction
+		wantFiltered      bool
+		wantPenalized     bool
+		wantScore         int
+		wantPriorityScore float64
+	}{
+		{
+			name:      "default actions",
+			wantScore: 0,
+		},
+		{
+			name:            "reject by predicate",
+			predicateAction: policy.MissingDataReject,
+			wantFiltered:    true,
+		},
+		{
+			name:            "penalize by predicate",
+			predicateAction: policy.MissingDataPenalizeScore,
+			wantPenalized:   true,
+		},
+		{
+			name:           "reject by priority",
+			priorityAction: policy.MissingDataReject,
+			wantPenalized:  true,
+		},
+		{
+			name:           "ignore priority",
+			priorityAction: policy.MissingDataIgnore,
+			// only mem_usage_avg_5m is counted, which is missing and regarded as fully used.
+			wantScore: 0,
+		},
+		{
+			name:            "use last known",
+			predicateAction: policy.MissingDataUseLastKnown,
+			priorityAction:  policy.MissingDataUseLastKnown,
+			wantFiltered:    true,
+			// (1 - 0.75) * 0.5 * 100 = 12.5, averaged with 0 of mem_usage_avg_5m.
+			wantScore:         12,
+			wantPriorityScore: 12.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policySpec := policy.PolicySpec{
+				SyncPeriod: []policy.SyncPolicy{
+					{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+					{Name: "mem_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+				},
+				Predicate: []policy.PredicatePolicy{
+					{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65, MissingDataAction: tt.predicateAction},
+				},
+				Priority: []policy.PriorityPolicy{
+					{Name: "cpu_usage_avg_5m", Weight: 0.5, MissingDataAction: tt.priorityAction},
+					{Name: "mem_usage_avg_5m", Weight: 0.5},
+				},
+			}
+
+			e := Explain(&v1.Pod{}, []*v1.Node{node}, policySpec)[0]
+			if e.Filtered != tt.wantFiltered {
+				t.Errorf("got filtered %t (%s), want %t", e.Filtered, e.Reason, tt.wantFiltered)
+			}
+			if e.Penalized != tt.wantPenalized {
+				t.Errorf("got penalized %t, want %t", e.Penalized, tt.wantPenalized)
+			}
+			if !e.Penalized && e.Score != tt.wantScore {
+				t.Errorf("got score %d, want %d", e.Score, tt.wantScore)
+			}
+			if e.Priorities[0].Score != tt.wantPriorityScore {
+				t.Errorf("got cpu score %f, want %f", e.Priorities[0].Score, tt.wantPriorityScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package dynamic
+
+import (
+	"context"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+var _ framework.ReservePlugin = &DynamicScheduler{}
+
+const (
+	// defaultBindingHeapSize is the size of binding records if not set in plugin args.
+	defaultBindingHeapSize = 1024
+	// bindingsGCPeriod is the period to recycle expired binding records.
+	bindingsGCPeriod = time.Minute
+)
+
+// Reserve invoked at the reserve extension point.
+// It records the binding of the pod, so that the hot value of the node rises right
+// away instead of waiting for the annotator to sync it. The nomination of the pod
+// is no longer needed then.
+func (ds *DynamicScheduler) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	ds.nominations.remove(pod.UID)
+	ds.bindingRecords.AddBinding(&utils.Binding{
+		Node:      nodeName,
+		Namespace: pod.Namespace,
+		PodName:   pod.Name,
+		Timestamp: time.Now().UTC().Unix(),
+	})
+	return nil
+}
+
+// Unreserve invoked if the pod is rejected or fails to be bound after Reserve.
+// It removes the binding recorded at Reserve.
+func (ds *DynamicScheduler) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+	ds.bindingRecords.RemoveBinding(pod.Namespace, pod.Name)
+}
+
+// getHotValue returns the hot value of the node, which is the larger one of the hot
+// value annotation and the one computed from bindings made by this scheduler. The
+// annotation covers bindings made by other schedulers, but lags by its sync period.
+func (ds *DynamicScheduler) getHotValue(nodeName string, load *nodeLoad, hotValues []policy.HotValuePolicy) float64 {
+	ages := ds.bindingRecords.GetLastNodeBindingAges(nodeName, policy.GetMaxHotValueTimeRange(hotValues))
+
+	return math.Max(getNodeHotValue(nodeName, load), policy.GetHotValue(hotValues, ages))
+}
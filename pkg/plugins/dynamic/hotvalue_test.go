@@ -0,0 +1,45 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestReserveHotValue(t *testing.T) {
+	ds := &DynamicScheduler{bindingRecords: utils.NewBindingRecords(10, time.Minute), nominations: newNominationRecords()}
+	hotValues := []policy.HotValuePolicy{{TimeRange: metav1.Duration{Duration: time.Minute}, Count: 1}}
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	load := newNodeLoadCache().get(node)
+
+	for _, name := range []string{"pod-1", "pod-2"} {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		if status := ds.Reserve(context.TODO(), nil, pod, node.Name); !status.IsSuccess() {
+			t.Fatalf("failed to reserve pod %s: %v", name, status)
+		}
+	}
+	if got := ds.getHotValue(node.Name, load, hotValues); got != 20 {
+		t.Errorf("got hot value %f after reserving 2 pods, want 20", got)
+	}
+
+	ds.Unreserve(context.TODO(), nil, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}}, node.Name)
+	if got := ds.getHotValue(node.Name, load, hotValues); got != 10 {
+		t.Errorf("got hot value %f after unreserving 1 pod, want 10", got)
+	}
+
+	// the annotation counting bindings of other schedulers goes first if larger.
+	annotated := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node-1",
+		Annotations: map[string]string{NodeHotValue: "30," + utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)},
+	}}
+	if got := ds.getHotValue(node.Name, newNodeLoadCache().get(annotated), hotValues); got != 30 {
+		t.Errorf("got hot value %f with annotation 30, want 30", got)
+	}
+}
@@ -0,0 +1,102 @@
+package dynamic
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+// nodeLoad is the load data of a node, which is read from the structured
+// annotation first, and then from legacy per-metric annotations.
+type nodeLoad struct {
+	annotations map[string]string
+	structured  *utils.NodeLoad
+	// anticipated is the estimated usage of pods bound recently, by metric name.
+	anticipated map[string]float64
+	// incoming is the estimated usage of the pod being scheduled, by metric name,
+	// which is only added at Filter.
+	incoming map[string]float64
+}
+
+// getResourceUsage returns the value of metric key if it is updated within activeDuration.
+func (l *nodeLoad) getResourceUsage(key string, activeDuration time.Duration) (float64, error) {
+	return utils.GetNodeLoadUsage(l.annotations, l.structured, key, activeDuration)
+}
+
+type nodeLoadCacheEntry struct {
+	data string
+	load *utils.NodeLoad
+}
+
+// nodeLoadCache caches decoded structured annotations of nodes, so that they are
+// decoded only once after updated, instead of in every Filter and Score call.
+type nodeLoadCache struct {
+	rw      sync.RWMutex
+	entries map[string]*nodeLoadCacheEntry
+}
+
+func newNodeLoadCache() *nodeLoadCache {
+	return &nodeLoadCache{
+		entries: make(map[string]*nodeLoadCacheEntry),
+	}
+}
+
+// get returns the load data of the node.
+func (c *nodeLoadCache) get(node *v1.Node) *nodeLoad {
+	annotations := node.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	load := &nodeLoad{annotations: annotations}
+
+	data, ok := annotations[utils.NodeLoadAnnotationKey]
+	if !ok {
+		return load
+	}
+
+	c.rw.RLock()
+	entry, ok := c.entries[node.Name]
+	c.rw.RUnlock()
+	if ok && entry.data == data {
+		load.structured = entry.load
+		return load
+	}
+
+	structured, err := utils.ParseNodeLoad(data)
+	if err != nil {
+		klog.Errorf("[crane] failed to decode load annotation of node[%s]: %v", node.Name, err)
+		return load
+	}
+
+	c.rw.Lock()
+	c.entries[node.Name] = &nodeLoadCacheEntry{data: data, load: structured}
+	c.rw.Unlock()
+
+	load.structured = structured
+	return load
+}
+
+// remove drops the cached load of the node.
+func (c *nodeLoadCache) remove(nodeName string) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	delete(c.entries, nodeName)
+}
+
+// handleDeleteNode drops the cached load of the deleted node.
+func (ds *DynamicScheduler) handleDeleteNode(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	if node, ok := obj.(*v1.Node); ok {
+		ds.loadCache.remove(node.Name)
+	}
+}
@@ -0,0 +1,133 @@
+package dynamic
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestNodeLoadGetResourceUsage(t *testing.T) {
+	now := time.Now()
+	fresh := utils.FormatTimestamp(now, utils.RFC3339TimestampFormat)
+
+	loadData, err := utils.FormatNodeLoad(&utils.NodeLoad{Metrics: map[string]utils.MetricSample{
+		"cpu_usage_avg_5m":     {Value: 0.3, Timestamp: now},
+		"cpu_usage_max_avg_1h": {Value: 0.6, Timestamp: now.Add(-time.Hour)},
+	}})
+	if err != nil {
+		t.Fatalf("failed to encode node load: %v", err)
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		Annotations: map[string]string{
+			utils.NodeLoadAnnotationKey: loadData,
+			"cpu_usage_avg_5m":          "0.90000," + fresh,
+			"cpu_usage_max_avg_1h":      "0.90000," + fresh,
+			"mem_usage_avg_5m":          "0.40000," + fresh,
+		},
+	}}
+
+	load := newNodeLoadCache().get(node)
+
+	tests := []struct {
+		name        string
+		key         string
+		wantUsage   float64
+		wantExpired bool
+	}{
+		{name: "structured annotation is preferred", key: "cpu_usage_avg_5m", wantUsage: 0.3},
+		{name: "expired structured annotation", key: "cpu_usage_max_avg_1h", wantExpired: true},
+		{name: "fall back to legacy annotation", key: "mem_usage_avg_5m", wantUsage: 0.4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := load.getResourceUsage(tt.key, 5*time.Minute)
+			if tt.wantExpired {
+				if _, ok := err.(*utils.ExpiredError); !ok {
+					t.Errorf("got error %v, want expired error", err)
+				}
+				return
+			}
+			if err != nil || usage != tt.wantUsage {
+				t.Errorf("got usage %v and error %v, want %v", usage, err, tt.wantUsage)
+			}
+		})
+	}
+}
+
+func TestNodeLoadCache(t *testing.T) {
+	now := time.Now()
+	loadData, err := utils.FormatNodeLoad(&utils.NodeLoad{Metrics: map[string]utils.MetricSample{
+		"cpu_usage_avg_5m": {Value: 0.3, Timestamp: now},
+	}})
+	if err != nil {
+		t.Fatalf("failed to encode node load: %v", err)
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		Annotations: map[string]string{
+			utils.NodeLoadAnnotationKey: loadData,
+			"mem_usage_avg_5m":          "0.40000," + utils.FormatTimestamp(now, utils.RFC3339TimestampFormat),
+		},
+	}}
+
+	loadCache := newNodeLoadCache()
+
+	first := loadCache.get(node).structured
+	if first == nil || first.Metrics["cpu_usage_avg_5m"].Value != 0.3 {
+		t.Fatalf("got structured load %v, want cpu_usage_avg_5m 0.3", first)
+	}
+	if loadCache.get(node).structured != first {
+		t.Errorf("unchanged annotation is decoded again")
+	}
+
+	if node.Annotations[utils.NodeLoadAnnotationKey], err = utils.FormatNodeLoad(&utils.NodeLoad{Metrics: map[string]utils.MetricSample{
+		"cpu_usage_avg_5m": {Value: 0.5, Timestamp: now},
+	}}); err != nil {
+		t.Fatalf("failed to encode node load: %v", err)
+	}
+	if updated := loadCache.get(node).structured; updated == first || updated.Metrics["cpu_usage_avg_5m"].Value != 0.5 {
+		t.Errorf("got structured load %v after update, want cpu_usage_avg_5m 0.5", updated)
+	}
+
+	// illegal structured annotation falls back to legacy annotations.
+	node.Annotations[utils.NodeLoadAnnotationKey] = "{"
+	load := loadCache.get(node)
+	if load.structured != nil {
+		t.Errorf("got structured load %v of illegal annotation, want nil", load.structured)
+	}
+	if usage, err := load.getResourceUsage("mem_usage_avg_5m", 5*time.Minute); err != nil || usage != 0.4 {
+		t.Errorf("got usage %v and error %v, want legacy value 0.4", usage, err)
+	}
+
+	if load := loadCache.get(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}); load.structured != nil || load.annotations == nil {
+		t.Errorf("got load %+v of node without annotations, want legacy load with empty annotations", load)
+	}
+}
+
+func TestHandleDeleteNode(t *testing.T) {
+	loadData, err := utils.FormatNodeLoad(&utils.NodeLoad{Metrics: map[string]utils.MetricSample{
+		"cpu_usage_avg_5m": {Value: 0.3, Timestamp: time.Now()},
+	}})
+	if err != nil {
+		t.Fatalf("failed to encode node load: %v", err)
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node-1",
+		Annotations: map[string]string{utils.NodeLoadAnnotationKey: loadData},
+	}}
+
+	ds := &DynamicScheduler{loadCache: newNodeLoadCache()}
+	ds.loadCache.get(node)
+
+	ds.handleDeleteNode(cache.DeletedFinalStateUnknown{Key: "node-1", Obj: node})
+	if len(ds.loadCache.entries) != 0 {
+		t.Errorf("got %d cached loads after node deleted, want 0", len(ds.loadCache.entries))
+	}
+}
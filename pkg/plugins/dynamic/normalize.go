@@ -0,0 +1,90 @@
+package dynamic
+
+import (
+	"context"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+var _ framework.ScoreExtensions = &DynamicScheduler{}
+
+// NormalizeScore invoked after scoring all nodes.
+// It normalizes node scores across candidate nodes by the score normalization of the
+// policy, so that small load differences still make a difference in scores.
+func (ds *DynamicScheduler) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	policyState := ds.getPolicyState(state, pod)
+	if policyState.ignoreLoad {
+		return nil
+	}
+
+	normalizeScores(scores, policyState.spec.ScoreNormalization)
+
+	return nil
+}
+
+func normalizeScores(scores framework.NodeScoreList, mode policy.ScoreNormalization) {
+	switch mode {
+	case policy.ScoreNormalizationMinMax:
+		normalizeByMinMax(scores)
+	case policy.ScoreNormalizationRank:
+		normalizeByRank(scores)
+	}
+}
+
+// normalizeByMinMax scales scores linearly to [MinNodeScore, MaxNodeScore]. Scores
+// are kept as they are if all of them are the same.
+func normalizeByMinMax(scores framework.NodeScoreList) {
+	if len(scores) == 0 {
+		return
+	}
+
+	min, max := scores[0].Score, scores[0].Score
+	for _, s := range scores {
+		if s.Score < min {
+			min = s.Score
+		}
+		if s.Score > max {
+			max = s.Score
+		}
+	}
+
+	if max == min {
+		return
+	}
+
+	for i := range scores {
+		scores[i].Score = framework.MinNodeScore + (scores[i].Score-min)*(framework.MaxNodeScore-framework.MinNodeScore)/(max-min)
+	}
+}
+
+// normalizeByRank spreads scores evenly in [MinNodeScore, MaxNodeScore] by the rank
+// of distinct scores. Scores are kept as they are if all of them are the same.
+func normalizeByRank(scores framework.NodeScoreList) {
+	var distinct []int64
+	seen := map[int64]bool{}
+	for _, s := range scores {
+		if !seen[s.Score] {
+			seen[s.Score] = true
+			distinct = append(distinct, s.Score)
+		}
+	}
+
+	if len(distinct) <= 1 {
+		return
+	}
+
+	sort.Slice(distinct, func(i, j int) bool { return distinct[i] < distinct[j] })
+
+	ranks := make(map[int64]int64, len(distinct))
+	for i, score := range distinct {
+		ranks[score] = int64(i)
+	}
+
+	for i := range scores {
+		scores[i].Score = framework.MinNodeScore + ranks[scores[i].Score]*(framework.MaxNodeScore-framework.MinNodeScore)/int64(len(distinct)-1)
+	}
+}
@@ -0,0 +1,74 @@
+package dynamic
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+func TestNormalizeScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   policy.ScoreNormalization
+		scores []int64
+		want   []int64
+	}{
+		{
+			name:   "not set",
+			scores: []int64{40, 50, 60},
+			want:   []int64{40, 50, 60},
+		},
+		{
+			name:   "none",
+			mode:   policy.ScoreNormalizationNone,
+			scores: []int64{40, 50, 60},
+			want:   []int64{40, 50, 60},
+		},
+		{
+			name:   "min-max",
+			mode:   policy.ScoreNormalizationMinMax,
+			scores: []int64{40, 50, 60, 45},
+			want:   []int64{0, 50, 100, 25},
+		},
+		{
+			name:   "min-max of the same scores",
+			mode:   policy.ScoreNormalizationMinMax,
+			scores: []int64{40, 40},
+			want:   []int64{40, 40},
+		},
+		{
+			name:   "rank",
+			mode:   policy.ScoreNormalizationRank,
+			scores: []int64{41, 60, 42, 41, 50},
+			want:   []int64{0, 100, 33, 0, 66},
+		},
+		{
+			name:   "rank of a single node",
+			mode:   policy.ScoreNormalizationRank,
+			scores: []int64{42},
+			want:   []int64{42},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scores framework.NodeScoreList
+			for _, score := range tt.scores {
+				scores = append(scores, framework.NodeScore{Score: score})
+			}
+
+			normalizeScores(scores, tt.mode)
+
+			var got []int64
+			for _, s := range scores {
+				got = append(got, s.Score)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got scores %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
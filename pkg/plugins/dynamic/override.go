@@ -0,0 +1,176 @@
+package dynamic
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+const (
+	// PolicyOverrideAnnotationKey is the pod annotation to choose a policy override by name.
+	PolicyOverrideAnnotationKey = "scheduler.crane.io/dynamic-policy"
+
+	// policyStateKey is the key in CycleState to the effective policy of the pod.
+	policyStateKey framework.StateKey = Name + "/policy"
+)
+
+// policyState is the effective policy of the pod resolved at PreFilter.
+type policyState struct {
+	// override is the name of the policy override in effect, empty if none.
+	override   string
+	ignoreLoad bool
+	// predicate and priority of the policy override, which go before those of
+	// node pools if not nil.
+	predicate []policy.PredicatePolicy
+	priority  []policy.PriorityPolicy
+
+	spec      policy.PolicySpec
+	nodePools *policy.NodePoolSelector
+}
+
+// Clone the policy state, which is never modified after written.
+func (s *policyState) Clone() framework.StateData {
+	return s
+}
+
+// specOf returns the effective policy spec for the node, and the name of the node
+// pool which the node belongs to.
+func (s *policyState) specOf(node *v1.Node) (policy.PolicySpec, string) {
+	var poolName string
+
+	pool := s.nodePools.Select(node.Labels)
+	if pool != nil {
+		poolName = pool.Name
+	}
+
+	spec := policy.ApplyNodePool(s.spec, pool)
+	if s.predicate != nil {
+		spec.Predicate = s.predicate
+	}
+	if s.priority != nil {
+		spec.Priority = s.priority
+	}
+
+	return spec, poolName
+}
+
+// overrideSelector finds the policy override of a pod, with pod selectors of
+// overrides parsed once when the policy is loaded.
+type overrideSelector struct {
+	overrides  []policy.PolicyOverride
+	namespaces []sets.String
+	// selectors are nil for overrides without pod selector, and select nothing for
+	// overrides with illegal ones, which are rejected by validation anyway.
+	selectors []labels.Selector
+}
+
+// newOverrideSelector parses pod selectors of policy overrides.
+func newOverrideSelector(overrides []policy.PolicyOverride) *overrideSelector {
+	s := &overrideSelector{overrides: overrides}
+
+	for i := range overrides {
+		s.namespaces = append(s.namespaces, sets.NewString(overrides[i].Namespaces...))
+
+		var selector labels.Selector
+		if overrides[i].PodSelector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(overrides[i].PodSelector); err != nil {
+				selector = labels.Nothing()
+			}
+		}
+		s.selectors = append(s.selectors, selector)
+	}
+
+	return s
+}
+
+// find returns the policy override of the pod. The override chosen by pod annotation
+// goes first, and then the first override selecting the pod.
+func (s *overrideSelector) find(pod *v1.Pod) *policy.PolicyOverride {
+	if s == nil {
+		return nil
+	}
+
+	if name, ok := pod.Annotations[PolicyOverrideAnnotationKey]; ok {
+		for i := range s.overrides {
+			if s.overrides[i].Name == name {
+				return &s.overrides[i]
+			}
+		}
+		klog.Warningf("[crane] policy override %s of pod %s/%s is not found", name, pod.Namespace, pod.Name)
+	}
+
+	for i := range s.overrides {
+		if s.selects(i, pod) {
+			return &s.overrides[i]
+		}
+	}
+
+	return nil
+}
+
+// selects returns whether the i-th override selects the pod by namespaces and pod selector.
+func (s *overrideSelector) selects(i int, pod *v1.Pod) bool {
+	if s.namespaces[i].Len() == 0 && s.selectors[i] == nil {
+		return false
+	}
+	if s.namespaces[i].Len() > 0 && !s.namespaces[i].Has(pod.Namespace) {
+		return false
+	}
+	if s.selectors[i] != nil && !s.selectors[i].Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+
+	return true
+}
+
+// PreFilter invoked at the prefilter extension point.
+// It resolves the effective policy of the pod and caches it in CycleState, so that
+// Filter and Score of the same scheduling cycle use the same policy.
+func (ds *DynamicScheduler) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) *framework.Status {
+	schedulerPolicy, nodePools, overrides := ds.getPolicyWithSelectors()
+	state.Write(policyStateKey, resolvePolicy(pod, schedulerPolicy.Spec, nodePools, overrides))
+
+	return nil
+}
+
+// PreFilterExtensions returns prefilter extensions, pod add and remove.
+func (ds *DynamicScheduler) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// getPolicyState returns the effective policy of the pod from CycleState, or
+// resolves it if PreFilter is not enabled.
+func (ds *DynamicScheduler) getPolicyState(state *framework.CycleState, pod *v1.Pod) *policyState {
+	if c, err := state.Read(policyStateKey); err == nil {
+		if s, ok := c.(*policyState); ok {
+			return s
+		}
+	}
+
+	schedulerPolicy, nodePools, overrides := ds.getPolicyWithSelectors()
+	return resolvePolicy(pod, schedulerPolicy.Spec, nodePools, overrides)
+}
+
+// resolvePolicy returns the effective policy of the pod, where nodePools and overrides
+// are parsed from node pools and policy overrides of the spec.
+func resolvePolicy(pod *v1.Pod, spec policy.PolicySpec, nodePools *policy.NodePoolSelector, overrides *overrideSelector) *policyState {
+	s := &policyState{
+		spec:      spec,
+		nodePools: nodePools,
+	}
+
+	if override := overrides.find(pod); override != nil {
+		s.override, s.ignoreLoad = override.Name, override.IgnoreLoad
+		s.predicate, s.priority = override.Predicate, override.Priority
+	}
+
+	return s
+}
@@ -0,0 +1,187 @@
+package dynamic
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+func TestResolvePolicy(t *testing.T) {
+	spec := policy.PolicySpec{
+		Predicate: []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65}},
+		Priority:  []policy.PriorityPolicy{{Name: "cpu_usage_avg_5m", Weight: 1}},
+		Overrides: []policy.PolicyOverride{
+			{
+				Name:      "latency-sensitive",
+				Predicate: []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.4}},
+			},
+			{
+				Name:       "batch",
+				Namespaces: []string{"batch"},
+				IgnoreLoad: true,
+			},
+			{
+				Name:        "online",
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "online"}},
+				Priority:    []policy.PriorityPolicy{{Name: "cpu_usage_avg_5m", Weight: 2}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		pod            *v1.Pod
+		wantOverride   string
+		wantIgnoreLoad bool
+		wantThreshold  float64
+		wantWeight     float64
+	}{
+		{
+			name:          "no override",
+			pod:           &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}},
+			wantThreshold: 0.65,
+			wantWeight:    1,
+		},
+		{
+			name: "chosen by annotation",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "batch",
+				Annotations: map[string]string{PolicyOverrideAnnotationKey: "latency-sensitive"},
+			}},
+			wantOverride:  "latency-sensitive",
+			wantThreshold: 0.4,
+			wantWeight:    1,
+		},
+		{
+			name: "unknown annotation falls back to selectors",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "batch",
+				Annotations: map[string]string{PolicyOverrideAnnotationKey: "unknown"},
+			}},
+			wantOverride:   "batch",
+			wantIgnoreLoad: true,
+			wantThreshold:  0.65,
+			wantWeight:     1,
+		},
+		{
+			name: "selected by labels",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Labels:    map[string]string{"tier": "online"},
+			}},
+			wantOverride:  "online",
+			wantThreshold: 0.65,
+			wantWeight:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := resolvePolicy(tt.pod, spec, policy.NewNodePoolSelector(spec.NodePools), newOverrideSelector(spec.Overrides))
+			if s.override != tt.wantOverride || s.ignoreLoad != tt.wantIgnoreLoad {
+				t.Errorf("got override %q and ignoreLoad %t, want %q and %t", s.override, s.ignoreLoad, tt.wantOverride, tt.wantIgnoreLoad)
+			}
+
+			nodeSpec, _ := s.specOf(&v1.Node{})
+			if nodeSpec.Predicate[0].MaxLimitPecent != tt.wantThreshold {
+				t.Errorf("got threshold %f, want %f", nodeSpec.Predicate[0].MaxLimitPecent, tt.wantThreshold)
+			}
+			if nodeSpec.Priority[0].Weight != tt.wantWeight {
+				t.Errorf("got weight %f, want %f", nodeSpec.Priority[0].Weight, tt.wantWeight)
+			}
+		})
+	}
+}
+
+func TestPolicyStateSpecOf(t *testing.T) {
+	spec := policy.PolicySpec{
+		Predicate: []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65}},
+		Priority:  []policy.PriorityPolicy{{Name: "cpu_usage_avg_5m", Weight: 1}},
+		Overrides: []policy.PolicyOverride{
+			{
+				Name:      "latency-sensitive",
+				Predicate: []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.4}},
+			},
+		},
+		NodePools: []policy.NodePoolPolicy{
+			{
+				Name:         "gpu",
+				NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+				Predicate:    []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.8}},
+				Priority:     []policy.PriorityPolicy{{Name: "cpu_usage_avg_5m", Weight: 3}},
+			},
+		},
+	}
+
+	gpuNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pool": "gpu"}}}
+
+	nodePools, overrides := policy.NewNodePoolSelector(spec.NodePools), newOverrideSelector(spec.Overrides)
+
+	nodeSpec, pool := resolvePolicy(&v1.Pod{}, spec, nodePools, overrides).specOf(gpuNode)
+	if pool != "gpu" || nodeSpec.Predicate[0].MaxLimitPecent != 0.8 || nodeSpec.Priority[0].Weight != 3 {
+		t.Errorf("got pool %q, threshold %f and weight %f, want rules of pool gpu", pool, nodeSpec.Predicate[0].MaxLimitPecent, nodeSpec.Priority[0].Weight)
+	}
+
+	// predicates of the pod override go before those of the node pool.
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PolicyOverrideAnnotationKey: "latency-sensitive"}}}
+	nodeSpec, _ = resolvePolicy(pod, spec, nodePools, overrides).specOf(gpuNode)
+	if nodeSpec.Predicate[0].MaxLimitPecent != 0.4 || nodeSpec.Priority[0].Weight != 3 {
+		t.Errorf("got threshold %f and weight %f, want 0.4 and 3", nodeSpec.Predicate[0].MaxLimitPecent, nodeSpec.Priority[0].Weight)
+	}
+}
+
+func TestOverrideSelector(t *testing.T) {
+	overrides := newOverrideSelector([]policy.PolicyOverride{
+		{
+			Name: "illegal",
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Equals"}},
+			},
+		},
+		{
+			Name:        "online",
+			Namespaces:  []string{"default", "online"},
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "online"}},
+		},
+	})
+
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want string
+	}{
+		{
+			name: "selected by namespace and labels",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "online", Labels: map[string]string{"tier": "online", "app": "web"}}},
+			want: "online",
+		},
+		{
+			name: "namespace not selected",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Labels: map[string]string{"tier": "online"}}},
+		},
+		{
+			name: "override with illegal selector is still chosen by annotation",
+			pod:  &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PolicyOverrideAnnotationKey: "illegal"}}},
+			want: "illegal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if override := overrides.find(tt.pod); override != nil {
+				got = override.Name
+			}
+			if got != tt.want {
+				t.Errorf("got override %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if override := (*overrideSelector)(nil).find(&v1.Pod{}); override != nil {
+		t.Errorf("got override %q from nil selector, want none", override.Name)
+	}
+}
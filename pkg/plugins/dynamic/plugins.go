@@ -3,19 +3,29 @@ package dynamic
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/config"
 	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy/validation"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 	"github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
+var _ framework.PreFilterPlugin = &DynamicScheduler{}
 var _ framework.FilterPlugin = &DynamicScheduler{}
 var _ framework.ScorePlugin = &DynamicScheduler{}
+var _ io.Closer = &DynamicScheduler{}
 
 const (
 	// Name is the name of the plugin used in the plugin registry and configurations.
@@ -24,8 +34,33 @@ const (
 
 // Dynamic-scheduler is a real load-aware scheduler plugin.
 type DynamicScheduler struct {
-	handle          framework.Handle
+	handle framework.Handle
+
+	// policyLock guards schedulerPolicy, policyContent, and nodePools and overrides
+	// parsed from the policy, which may be swapped at any time when the policy file changes,
+	// and rejectedContent, the last illegal content of the policy file, which is
+	// not retried.
+	policyLock      sync.RWMutex
 	schedulerPolicy *policy.DynamicSchedulerPolicy
+	policyContent   []byte
+	nodePools       *policy.NodePoolSelector
+	overrides       *overrideSelector
+	rejectedContent []byte
+
+	loadCache *nodeLoadCache
+	// bindingRecords keeps bindings made by this scheduler to compute hot values.
+	bindingRecords *utils.BindingRecords
+
+	nodeLister corelisters.NodeLister
+	// nominations keeps nodes nominated by PostFilter, on which thresholds are raised.
+	nominations *nominationRecords
+
+	// degraded is 1 if Filter is degraded to score-only mode by the safety valve.
+	degraded int32
+
+	// stopCh stops the policy watcher and background workers when closed by Close.
+	stopCh    chan struct{}
+	closeOnce sync.Once
 }
 
 // Name returns name of the plugin.
@@ -47,27 +82,52 @@ func (ds *DynamicScheduler) Filter(ctx context.Context, state *framework.CycleSt
 		return framework.NewStatus(framework.Error, "node not found")
 	}
 
-	nodeAnnotations, nodeName := nodeInfo.Node().Annotations, nodeInfo.Node().Name
-	if nodeAnnotations == nil {
-		nodeAnnotations = map[string]string{}
+	policyState := ds.getPolicyState(state, pod)
+	if policyState.ignoreLoad {
+		return framework.NewStatus(framework.Success, "")
+	}
+
+	if ds.isDegraded() {
+		klog.V(4).Infof("[crane] ignore the load of node[%s] for Filter is degraded to score-only mode", node.Name)
+		return framework.NewStatus(framework.Success, "")
 	}
 
-	for _, policy := range ds.schedulerPolicy.Spec.Predicate {
-		activeDuration, err := getActiveDuration(ds.schedulerPolicy.Spec.SyncPeriod, policy.Name)
+	nodeName := node.Name
+	spec, _ := policyState.specOf(node)
+	load := ds.getNodeLoad(pod, nodeInfo, spec)
+	tolerance := ds.getNominationTolerance(pod, nodeName, spec)
+
+	for _, predicatePolicy := range spec.Predicate {
+		activeDuration, err := policy.GetActiveDuration(spec.SyncPeriod, predicatePolicy.Name)
 
 		if err != nil || activeDuration == 0 {
 			klog.Warningf("[crane] failed to get active duration: %v", err)
 			continue
 		}
 
-		if isOverLoad(nodeName, nodeAnnotations, policy, activeDuration) {
-			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Load[%s] of node[%s] is too high", policy.Name, nodeName))
+		// thresholds are raised on the node nominated at PostFilter.
+		if tolerance > 0 && predicatePolicy.MaxLimitPecent != 0 {
+			predicatePolicy.MaxLimitPecent += tolerance
+		}
+
+		if reason := filterByPredicate(nodeName, load, predicatePolicy, activeDuration); reason != "" {
+			return framework.NewStatus(framework.Unschedulable, reason)
 		}
 
 	}
 	return framework.NewStatus(framework.Success, "")
 }
 
+// getNodeLoad returns the load data of the node, including the anticipated load of
+// pods bound recently and the load of the pod being scheduled.
+func (ds *DynamicScheduler) getNodeLoad(pod *v1.Pod, nodeInfo *framework.NodeInfo, spec policy.PolicySpec) *nodeLoad {
+	load := ds.loadCache.get(nodeInfo.Node())
+	load.anticipated = getAnticipatedLoad(nodeInfo, spec)
+	load.incoming = getIncomingLoad(pod, nodeInfo.Node(), spec)
+
+	return load
+}
+
 // Score invoked at the Score extension point.
 // It gets metric data from node annotation, and favors nodes with the least real resource usage.
 func (ds *DynamicScheduler) Score(ctx context.Context, state *framework.CycleState, p *v1.Pod, nodeName string) (int64, *framework.Status) {
@@ -81,24 +141,36 @@ func (ds *DynamicScheduler) Score(ctx context.Context, state *framework.CycleSta
 		return 0, framework.NewStatus(framework.Error, "node not found")
 	}
 
-	nodeAnnotations := node.Annotations
-	if nodeAnnotations == nil {
-		nodeAnnotations = map[string]string{}
+	policyState := ds.getPolicyState(state, p)
+	if policyState.ignoreLoad {
+		klog.V(4).Infof("[crane] ignore the load of node[%s] for pod %s/%s by policy override %s", node.Name, p.Namespace, p.Name, policyState.override)
+		return framework.MinNodeScore, nil
 	}
 
-	score, hotValue := getNodeScore(node.Name, nodeAnnotations, ds.schedulerPolicy.Spec), getNodeHotValue(node)
+	load := ds.loadCache.get(node)
 
-	score = score - int(hotValue*10)
+	spec, _ := policyState.specOf(node)
+	spec.Priority = weighPriorities(p, node, spec)
+	load.anticipated = getAnticipatedLoad(nodeInfo, spec)
+	score, penalized := getNodeScore(node.Name, load, spec)
+	hotValue := ds.getHotValue(node.Name, load, spec.HotValue)
 
-	finalScore := utils.NormalizeScore(int64(score),framework.MaxNodeScore,framework.MinNodeScore)
+	hotValuePenalty := getHotValuePenalty(hotValue)
+	finalScore := getFinalScore(score, hotValuePenalty, penalized)
+
+	if hotValuePenalty > 0 {
+		metrics.DynamicHotValuePenalty.Observe(float64(hotValuePenalty))
+	}
+	metrics.DynamicNodeScore.Observe(float64(finalScore))
 
-	klog.V(4).Infof("[crane] Node[%s]'s final score is %d, while score is %d and hot value is %f", node.Name, finalScore, score, hotValue)
+	klog.V(4).Infof("[crane] Node[%s]'s final score is %d, while score is %d, hot value is %f and penalized is %t", node.Name, finalScore, score, hotValue, penalized)
 
 	return finalScore, nil
 }
 
+// ScoreExtensions returns score extensions, which normalize node scores.
 func (ds *DynamicScheduler) ScoreExtensions() framework.ScoreExtensions {
-	return nil
+	return ds
 }
 
 // NewDynamicScheduler returns a Crane Scheduler object.
@@ -108,13 +180,61 @@ func NewDynamicScheduler(plArgs runtime.Object, h framework.Handle) (framework.P
 		return nil, fmt.Errorf("want args to be of type DynamicArgs, got %T.", plArgs)
 	}
 
-	schedulerPolicy, err := LoadPolicyFromFile(args.PolicyConfigPath)
+	data, err := ioutil.ReadFile(args.PolicyConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduler policy config file: %v", err)
+	}
+
+	schedulerPolicy, err := loadPolicy(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get scheduler policy from config file: %v", err)
 	}
 
-	return &DynamicScheduler{
+	if errs := validation.ValidateDynamicSchedulerPolicy(schedulerPolicy); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid scheduler policy: %v", errs.ToAggregate())
+	}
+
+	metrics.Register()
+
+	bindingHeapSize := args.BindingHeapSize
+	if bindingHeapSize <= 0 {
+		bindingHeapSize = defaultBindingHeapSize
+	}
+
+	ds := &DynamicScheduler{
 		schedulerPolicy: schedulerPolicy,
+		policyContent:   data,
+		nodePools:       policy.NewNodePoolSelector(schedulerPolicy.Spec.NodePools),
+		overrides:       newOverrideSelector(schedulerPolicy.Spec.Overrides),
+		loadCache:       newNodeLoadCache(),
+		bindingRecords:  utils.NewBindingRecords(bindingHeapSize, policy.GetMaxHotValueTimeRange(schedulerPolicy.Spec.HotValue)),
+		nodeLister:      h.SharedInformerFactory().Core().V1().Nodes().Lister(),
+		nominations:     newNominationRecords(),
 		handle:          h,
-	}, nil
+		stopCh:          make(chan struct{}),
+	}
+
+	h.SharedInformerFactory().Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: ds.handleDeleteNode,
+	})
+	h.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: ds.handleDeletePod,
+	})
+
+	ds.watchPolicyFile(args.PolicyConfigPath, ds.stopCh)
+	go wait.Until(ds.bindingRecords.BindingsGC, bindingsGCPeriod, ds.stopCh)
+	go wait.Until(ds.checkSafetyValve, safetyValveCheckPeriod, ds.stopCh)
+
+	return ds, nil
+}
+
+// Close stops the policy watcher and background workers of the plugin. Frameworks
+// close plugins implementing io.Closer when they are torn down, and so should anyone
+// creating the plugin outside of a framework, such as tests.
+func (ds *DynamicScheduler) Close() error {
+	ds.closeOnce.Do(func() {
+		close(ds.stopCh)
+	})
+
+	return nil
 }
@@ -0,0 +1,118 @@
+package dynamic
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy/validation"
+)
+
+const (
+	// PolicyResyncPeriod is the interval at which the policy file is re-read,
+	// in case that any file system notification is missed.
+	PolicyResyncPeriod = time.Minute
+)
+
+// getPolicy returns the scheduler policy currently in effect.
+func (ds *DynamicScheduler) getPolicy() *policy.DynamicSchedulerPolicy {
+	ds.policyLock.RLock()
+	defer ds.policyLock.RUnlock()
+
+	return ds.schedulerPolicy
+}
+
+// getPolicyWithSelectors returns the scheduler policy currently in effect, along
+// with node pools and policy overrides parsed from it.
+func (ds *DynamicScheduler) getPolicyWithSelectors() (*policy.DynamicSchedulerPolicy, *policy.NodePoolSelector, *overrideSelector) {
+	ds.policyLock.RLock()
+	defer ds.policyLock.RUnlock()
+
+	return ds.schedulerPolicy, ds.nodePools, ds.overrides
+}
+
+// watchPolicyFile watches the directory of the policy file, so that both in-place
+// writes and symlink swaps performed by ConfigMap volumes are noticed, and reloads
+// the policy whenever its content changes.
+func (ds *DynamicScheduler) watchPolicyFile(file string, stopCh <-chan struct{}) {
+	go wait.Until(func() { ds.reloadPolicy(file) }, PolicyResyncPeriod, stopCh)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		klog.Errorf("[crane] failed to create policy file watcher, fall back to periodic resync: %v", err)
+		return
+	}
+
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		klog.Errorf("[crane] failed to watch policy file %s, fall back to periodic resync: %v", file, err)
+		watcher.Close()
+		return
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				klog.V(5).Infof("[crane] policy file directory changed: %v", event)
+				ds.reloadPolicy(file)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				klog.Errorf("[crane] policy file watcher error: %v", err)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// reloadPolicy re-decodes the policy file and swaps the scheduler policy if the
+// content has changed. The last good policy is kept if the new one is illegal,
+// and the illegal content is remembered so that it is not reported again.
+func (ds *DynamicScheduler) reloadPolicy(file string) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		klog.Errorf("[crane] failed to read policy file %s, keep the current policy: %v", file, err)
+		return
+	}
+
+	ds.policyLock.Lock()
+	defer ds.policyLock.Unlock()
+
+	// skip the content in effect, and the rejected one until the file changes.
+	if bytes.Equal(data, ds.policyContent) || bytes.Equal(data, ds.rejectedContent) {
+		return
+	}
+
+	newPolicy, err := loadPolicy(data)
+	if err != nil {
+		klog.Errorf("[crane] failed to decode policy file %s, keep the current policy: %v", file, err)
+		ds.rejectedContent = data
+		return
+	}
+
+	if errs := validation.ValidateDynamicSchedulerPolicy(newPolicy); len(errs) > 0 {
+		klog.Errorf("[crane] invalid policy in file %s, keep the current policy: %v", file, errs.ToAggregate())
+		ds.rejectedContent = data
+		return
+	}
+
+	klog.Infof("[crane] scheduler policy reloaded from %s, diff: %s", file, diff.ObjectReflectDiff(ds.schedulerPolicy.Spec, newPolicy.Spec))
+
+	ds.schedulerPolicy, ds.policyContent, ds.rejectedContent = newPolicy, data, nil
+	ds.nodePools = policy.NewNodePoolSelector(newPolicy.Spec.NodePools)
+	ds.overrides = newOverrideSelector(newPolicy.Spec.Overrides)
+	ds.bindingRecords.SetGCTimeRange(policy.GetMaxHotValueTimeRange(newPolicy.Spec.HotValue))
+}
@@ -0,0 +1,138 @@
+package dynamic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+const policyTemplate = `apiVersion: scheduler.policy.crane.io/v1alpha1
+kind: DynamicSchedulerPolicy
+spec:
+  syncPolicy:
+    - name: cpu_usage_avg_5m
+      period: 3m
+  predicate:
+    - name: cpu_usage_avg_5m
+      maxLimitPecent: %v
+`
+
+func newPolicyWatcherTestScheduler(t *testing.T, file string) *DynamicScheduler {
+	schedulerPolicy, err := LoadPolicyFromFile(file)
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read policy: %v", err)
+	}
+
+	return &DynamicScheduler{
+		schedulerPolicy: schedulerPolicy,
+		policyContent:   data,
+		bindingRecords:  utils.NewBindingRecords(10, time.Minute),
+		stopCh:          make(chan struct{}),
+	}
+}
+
+func writePolicyFile(t *testing.T, file, content string) {
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write policy file: %v", err)
+	}
+}
+
+func waitForMaxLimitPercent(t *testing.T, ds *DynamicScheduler, want float64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := ds.getPolicy().Spec.Predicate[0].MaxLimitPecent
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got maxLimitPecent %v, want %v", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchPolicyFileReloadOnWrite(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicyFile(t, file, fmt.Sprintf(policyTemplate, 0.65))
+
+	ds := newPolicyWatcherTestScheduler(t, file)
+	defer ds.Close()
+	ds.watchPolicyFile(file, ds.stopCh)
+
+	writePolicyFile(t, file, fmt.Sprintf(policyTemplate, 0.75))
+	waitForMaxLimitPercent(t, ds, 0.75)
+}
+
+func TestReloadPolicyKeepsLastGoodPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicyFile(t, file, fmt.Sprintf(policyTemplate, 0.65))
+
+	ds := newPolicyWatcherTestScheduler(t, file)
+
+	for name, content := range map[string]string{
+		"undecodable": "spec: [",
+		"invalid":     fmt.Sprintf(policyTemplate, 1.5),
+	} {
+		writePolicyFile(t, file, content)
+		ds.reloadPolicy(file)
+		if got := ds.getPolicy().Spec.Predicate[0].MaxLimitPecent; got != 0.65 {
+			t.Errorf("%s: got maxLimitPecent %v, want the last good 0.65", name, got)
+		}
+		if string(ds.rejectedContent) != content {
+			t.Errorf("%s: rejected content is not remembered", name)
+		}
+	}
+
+	writePolicyFile(t, file, fmt.Sprintf(policyTemplate, 0.75))
+	ds.reloadPolicy(file)
+	if got := ds.getPolicy().Spec.Predicate[0].MaxLimitPecent; got != 0.75 {
+		t.Errorf("got maxLimitPecent %v after fixing the policy, want 0.75", got)
+	}
+	if ds.rejectedContent != nil {
+		t.Errorf("rejected content is not cleared after fixing the policy")
+	}
+}
+
+// TestWatchPolicyFileSymlinkSwap mimics how ConfigMap volumes are updated: the
+// file is a symlink through ..data, which is atomically swapped to a new directory.
+func TestWatchPolicyFileSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	writeVersion := func(version string, maxLimitPercent float64) {
+		versionDir := filepath.Join(dir, version)
+		if err := os.Mkdir(versionDir, 0755); err != nil {
+			t.Fatalf("failed to create version directory: %v", err)
+		}
+		writePolicyFile(t, filepath.Join(versionDir, "policy.yaml"), fmt.Sprintf(policyTemplate, maxLimitPercent))
+
+		tmpLink := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(version, tmpLink); err != nil {
+			t.Fatalf("failed to create symlink: %v", err)
+		}
+		if err := os.Rename(tmpLink, filepath.Join(dir, "..data")); err != nil {
+			t.Fatalf("failed to swap symlink: %v", err)
+		}
+	}
+
+	writeVersion("..v1", 0.65)
+	file := filepath.Join(dir, "policy.yaml")
+	if err := os.Symlink(filepath.Join("..data", "policy.yaml"), file); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	ds := newPolicyWatcherTestScheduler(t, file)
+	defer ds.Close()
+	ds.watchPolicyFile(file, ds.stopCh)
+
+	writeVersion("..v2", 0.75)
+	waitForMaxLimitPercent(t, ds, 0.75)
+}
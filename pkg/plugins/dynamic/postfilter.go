@@ -0,0 +1,198 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+var _ framework.PostFilterPlugin = &DynamicScheduler{}
+
+// defaultPostFilterCandidates is the number of the least loaded nodes reported by default.
+const defaultPostFilterCandidates = 3
+
+// overloadedNode is a node rejected for its load, and how much its usage exceeds thresholds.
+type overloadedNode struct {
+	name   string
+	margin float64
+}
+
+// PostFilter invoked at the postFilter extension point.
+// When nodes are rejected by the Dynamic plugin for their load, it reports the least
+// loaded ones by an event of the pod, and nominates the least loaded node if its usage
+// exceeds thresholds within the nomination tolerance.
+func (ds *DynamicScheduler) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	policyState := ds.getPolicyState(state, pod)
+	if policyState.ignoreLoad {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	var nodes []overloadedNode
+	for nodeName, status := range filteredNodeStatusMap {
+		if status.FailedPlugin() != Name {
+			continue
+		}
+
+		nodeInfo, err := ds.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+
+		spec, _ := policyState.specOf(nodeInfo.Node())
+		if margin, ok := getOverloadMargin(ds.getNodeLoad(pod, nodeInfo, spec), spec); ok {
+			nodes = append(nodes, overloadedNode{name: nodeName, margin: margin})
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	postFilter := policyState.spec.PostFilter
+	if postFilter == nil {
+		postFilter = &policy.PostFilterPolicy{}
+	}
+
+	nodes = leastLoadedNodes(nodes, postFilter.Candidates)
+	message := describeOverloadedNodes(nodes)
+
+	var nominated string
+	if postFilter.NominationTolerance > 0 && nodes[0].margin <= postFilter.NominationTolerance {
+		nominated = nodes[0].name
+		message = fmt.Sprintf("%s, nominated node %s within tolerance %.2f", message, nominated, postFilter.NominationTolerance)
+	}
+
+	klog.V(4).Infof("[crane] pod %s/%s is rejected for load: %s", pod.Namespace, pod.Name, message)
+	if recorder := ds.handle.EventRecorder(); recorder != nil {
+		recorder.Eventf(pod, nil, v1.EventTypeNormal, "LeastLoadedNodes", "PostFilter", message)
+	}
+
+	if nominated == "" {
+		ds.nominations.remove(pod.UID)
+		return nil, framework.NewStatus(framework.Unschedulable, message)
+	}
+
+	ds.nominations.set(pod.UID, nominated)
+	return framework.NewPostFilterResultWithNominatedNode(nominated), framework.NewStatus(framework.Success, message)
+}
+
+// getOverloadMargin returns the max amount by which the usage of the node exceeds
+// thresholds of predicates, and false if the node is not overloaded or is rejected
+// for missing data, which can not be tolerated.
+func getOverloadMargin(load *nodeLoad, policySpec policy.PolicySpec) (float64, bool) {
+	var margin float64
+
+	for _, predicatePolicy := range policySpec.Predicate {
+		activeDuration, err := policy.GetActiveDuration(policySpec.SyncPeriod, predicatePolicy.Name)
+		if err != nil || activeDuration == 0 {
+			continue
+		}
+
+		e := explainPredicate(load, predicatePolicy, activeDuration)
+		if e.MissingDataAction == policy.MissingDataReject {
+			return 0, false
+		}
+		if e.Overload {
+			margin = math.Max(margin, e.Usage-e.Threshold)
+		}
+	}
+
+	return margin, margin > 0
+}
+
+// leastLoadedNodes returns at most n nodes with the smallest overload margin.
+func leastLoadedNodes(nodes []overloadedNode, n int) []overloadedNode {
+	if n <= 0 {
+		n = defaultPostFilterCandidates
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].margin != nodes[j].margin {
+			return nodes[i].margin < nodes[j].margin
+		}
+		return nodes[i].name < nodes[j].name
+	})
+
+	if len(nodes) > n {
+		nodes = nodes[:n]
+	}
+
+	return nodes
+}
+
+func describeOverloadedNodes(nodes []overloadedNode) string {
+	var candidates []string
+	for _, node := range nodes {
+		candidates = append(candidates, fmt.Sprintf("%s(+%.2f)", node.name, node.margin))
+	}
+
+	return fmt.Sprintf("least loaded nodes exceeding thresholds: %s", strings.Join(candidates, ", "))
+}
+
+// getNominationTolerance returns how much thresholds are raised for the pod on the
+// node, which is the nomination tolerance if the pod is nominated to the node by
+// PostFilter of this plugin. Nominations made by others, such as preemption, do not
+// raise thresholds.
+func (ds *DynamicScheduler) getNominationTolerance(pod *v1.Pod, nodeName string, policySpec policy.PolicySpec) float64 {
+	if policySpec.PostFilter == nil || pod.Status.NominatedNodeName != nodeName || ds.nominations.get(pod.UID) != nodeName {
+		return 0
+	}
+
+	return policySpec.PostFilter.NominationTolerance
+}
+
+// handleDeletePod forgets the nomination of the deleted pod.
+func (ds *DynamicScheduler) handleDeletePod(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	if pod, ok := obj.(*v1.Pod); ok {
+		ds.nominations.remove(pod.UID)
+	}
+}
+
+// nominationRecords keeps the nodes nominated by PostFilter of this plugin, by pod
+// UID. A record is removed once the pod is reserved on any node or deleted.
+type nominationRecords struct {
+	lock  sync.Mutex
+	nodes map[types.UID]string
+}
+
+func newNominationRecords() *nominationRecords {
+	return &nominationRecords{
+		nodes: make(map[types.UID]string),
+	}
+}
+
+func (r *nominationRecords) set(uid types.UID, nodeName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.nodes[uid] = nodeName
+}
+
+func (r *nominationRecords) get(uid types.UID) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.nodes[uid]
+}
+
+func (r *nominationRecords) remove(uid types.UID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.nodes, uid)
+}
@@ -0,0 +1,232 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestGetOverloadMargin(t *testing.T) {
+	timestamp := utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)
+
+	policySpec := policy.PolicySpec{
+		SyncPeriod: []policy.SyncPolicy{
+			{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+			{Name: "mem_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}},
+		},
+		Predicate: []policy.PredicatePolicy{
+			{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65},
+			{Name: "mem_usage_avg_5m", MaxLimitPecent: 0.75, MissingDataAction: policy.MissingDataReject},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantMargin  float64
+		wantOK      bool
+	}{
+		{
+			name: "overloaded by the max margin",
+			annotations: map[string]string{
+				"cpu_usage_avg_5m": "0.70000," + timestamp,
+				"mem_usage_avg_5m": "0.85000," + timestamp,
+			},
+			wantMargin: 0.1,
+			wantOK:     true,
+		},
+		{
+			name: "not overloaded",
+			annotations: map[string]string{
+				"cpu_usage_avg_5m": "0.60000," + timestamp,
+				"mem_usage_avg_5m": "0.70000," + timestamp,
+			},
+		},
+		{
+			name:        "rejected for missing data",
+			annotations: map[string]string{"cpu_usage_avg_5m": "0.70000," + timestamp},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: tt.annotations}}
+
+			margin, ok := getOverloadMargin(newNodeLoadCache().get(node), policySpec)
+			if math.Abs(margin-tt.wantMargin) > 1e-9 || ok != tt.wantOK {
+				t.Errorf("got margin %f and %t, want %f and %t", margin, ok, tt.wantMargin, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLeastLoadedNodes(t *testing.T) {
+	nodes := []overloadedNode{
+		{name: "node-3", margin: 0.2},
+		{name: "node-2", margin: 0.05},
+		{name: "node-4", margin: 0.3},
+		{name: "node-1", margin: 0.05},
+	}
+
+	got := leastLoadedNodes(nodes, 0)
+	want := []string{"node-1", "node-2", "node-3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d nodes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].name != want[i] {
+			t.Errorf("got node %s at %d, want %s", got[i].name, i, want[i])
+		}
+	}
+}
+
+func TestGetNominationTolerance(t *testing.T) {
+	policySpec := policy.PolicySpec{PostFilter: &policy.PostFilterPolicy{NominationTolerance: 0.05}}
+	ds := &DynamicScheduler{nominations: newNominationRecords()}
+
+	nominated := &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "nominated"}, Status: v1.PodStatus{NominatedNodeName: "node-1"}}
+	ds.nominations.set(nominated.UID, "node-1")
+	if tolerance := ds.getNominationTolerance(nominated, "node-1", policySpec); tolerance != 0.05 {
+		t.Errorf("got tolerance %f on the nominated node, want 0.05", tolerance)
+	}
+	if tolerance := ds.getNominationTolerance(nominated, "node-2", policySpec); tolerance != 0 {
+		t.Errorf("got tolerance %f on other nodes, want 0", tolerance)
+	}
+
+	// nominated by preemption instead of this plugin.
+	preemptor := &v1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "preemptor"}, Status: v1.PodStatus{NominatedNodeName: "node-1"}}
+	if tolerance := ds.getNominationTolerance(preemptor, "node-1", policySpec); tolerance != 0 {
+		t.Errorf("got tolerance %f on the node nominated by preemption, want 0", tolerance)
+	}
+
+	// nominated by this plugin and then by preemption to another node.
+	ds.nominations.set(preemptor.UID, "node-2")
+	if tolerance := ds.getNominationTolerance(preemptor, "node-1", policySpec); tolerance != 0 {
+		t.Errorf("got tolerance %f on the node nominated by preemption, want 0", tolerance)
+	}
+
+	ds.handleDeletePod(cache.DeletedFinalStateUnknown{Obj: nominated})
+	if tolerance := ds.getNominationTolerance(nominated, "node-1", policySpec); tolerance != 0 {
+		t.Errorf("got tolerance %f after the pod is deleted, want 0", tolerance)
+	}
+}
+
+type fakeNodeInfoLister struct {
+	framework.NodeInfoLister
+	nodeInfos map[string]*framework.NodeInfo
+}
+
+func (l *fakeNodeInfoLister) Get(nodeName string) (*framework.NodeInfo, error) {
+	nodeInfo, ok := l.nodeInfos[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", nodeName)
+	}
+	return nodeInfo, nil
+}
+
+type fakeSharedLister struct {
+	nodeInfos *fakeNodeInfoLister
+}
+
+func (l *fakeSharedLister) NodeInfos() framework.NodeInfoLister {
+	return l.nodeInfos
+}
+
+// fakeHandle serves the node snapshot and the event recorder, which are all that
+// the Dynamic plugin needs from the framework.
+type fakeHandle struct {
+	framework.Handle
+	snapshot *fakeSharedLister
+	recorder *events.FakeRecorder
+}
+
+func (h *fakeHandle) SnapshotSharedLister() framework.SharedLister {
+	return h.snapshot
+}
+
+func (h *fakeHandle) EventRecorder() events.EventRecorder {
+	return h.recorder
+}
+
+func TestPostFilter(t *testing.T) {
+	timestamp := utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)
+
+	snapshot := &fakeSharedLister{nodeInfos: &fakeNodeInfoLister{nodeInfos: map[string]*framework.NodeInfo{}}}
+	for name, usage := range map[string]string{"node-1": "0.90000", "node-2": "0.68000", "node-3": "0.75000"} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{"cpu_usage_avg_5m": usage + "," + timestamp},
+		}})
+		snapshot.nodeInfos.nodeInfos[name] = nodeInfo
+	}
+
+	statuses := framework.NodeToStatusMap{
+		"node-1": framework.NewStatus(framework.Unschedulable, "overloaded").WithFailedPlugin(Name),
+		"node-2": framework.NewStatus(framework.Unschedulable, "overloaded").WithFailedPlugin(Name),
+		"node-3": framework.NewStatus(framework.Unschedulable, "no resources").WithFailedPlugin("NodeResourcesFit"),
+	}
+
+	tests := []struct {
+		name          string
+		tolerance     float64
+		wantNominated string
+		wantCode      framework.Code
+	}{
+		{name: "no nomination", wantCode: framework.Unschedulable},
+		{name: "least loaded node out of tolerance", tolerance: 0.01, wantCode: framework.Unschedulable},
+		{name: "least loaded node within tolerance", tolerance: 0.05, wantNominated: "node-2", wantCode: framework.Success},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := policy.PolicySpec{
+				SyncPeriod: []policy.SyncPolicy{{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}}},
+				Predicate:  []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65}},
+				PostFilter: &policy.PostFilterPolicy{NominationTolerance: tt.tolerance},
+			}
+			recorder := events.NewFakeRecorder(1)
+			ds := &DynamicScheduler{
+				handle:          &fakeHandle{snapshot: snapshot, recorder: recorder},
+				schedulerPolicy: &policy.DynamicSchedulerPolicy{Spec: spec},
+				loadCache:       newNodeLoadCache(),
+				nominations:     newNominationRecords(),
+			}
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", UID: "pod"}}
+
+			result, status := ds.PostFilter(context.TODO(), framework.NewCycleState(), pod, statuses)
+			if status.Code() != tt.wantCode {
+				t.Errorf("got status %v, want code %v", status, tt.wantCode)
+			}
+
+			var nominated string
+			if result != nil && result.NominatingInfo != nil {
+				nominated = result.NominatedNodeName
+			}
+			if nominated != tt.wantNominated || ds.nominations.get(pod.UID) != tt.wantNominated {
+				t.Errorf("got nominated node %q and recorded %q, want %q", nominated, ds.nominations.get(pod.UID), tt.wantNominated)
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, "node-2(+0.03), node-1(+0.25)") {
+					t.Errorf("got event %q, want the least loaded nodes rejected by this plugin", event)
+				}
+			default:
+				t.Errorf("no event is recorded")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package dynamic
+
+import (
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+// safetyValveCheckPeriod is how often overloaded nodes are counted for the safety valve.
+const safetyValveCheckPeriod = 30 * time.Second
+
+// checkSafetyValve counts nodes overloaded by predicates, and degrades Filter to
+// score-only mode while the ratio of them exceeds the limit of the safety valve.
+func (ds *DynamicScheduler) checkSafetyValve() {
+	spec := ds.getPolicy().Spec
+	if spec.SafetyValve == nil {
+		if ds.isDegraded() {
+			klog.Infof("[crane] safety valve is removed from the policy, resume Filter")
+			ds.recordSafetyValveEvent(v1.EventTypeNormal, "DynamicFilterResumed", "Safety valve is removed from the policy, nodes are filtered by load again")
+		}
+		ds.setDegraded(false)
+		return
+	}
+
+	nodes, err := ds.nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("[crane] failed to list nodes for the safety valve: %v", err)
+		return
+	}
+
+	overloaded := countOverloadedNodes(ds.loadCache, nodes, spec)
+	metrics.DynamicOverloadedNodes.Set(float64(overloaded))
+
+	degraded := len(nodes) > 0 && float64(overloaded)/float64(len(nodes)) > spec.SafetyValve.MaxOverloadedRatio
+	if degraded != ds.isDegraded() {
+		if degraded {
+			klog.Warningf("[crane] %d of %d nodes are overloaded, exceeding the ratio %.2f, degrade Filter to score-only mode", overloaded, len(nodes), spec.SafetyValve.MaxOverloadedRatio)
+			ds.recordSafetyValveEvent(v1.EventTypeWarning, "DynamicFilterDegraded", "%d of %d nodes are overloaded, nodes are not filtered by load until the ratio drops below %.2f", overloaded, len(nodes), spec.SafetyValve.MaxOverloadedRatio)
+		} else {
+			klog.Infof("[crane] %d of %d nodes are overloaded, resume Filter", overloaded, len(nodes))
+			ds.recordSafetyValveEvent(v1.EventTypeNormal, "DynamicFilterResumed", "%d of %d nodes are overloaded, nodes are filtered by load again", overloaded, len(nodes))
+		}
+	}
+	ds.setDegraded(degraded)
+}
+
+// recordSafetyValveEvent records an event when Filter is degraded or resumed. As the
+// state is cluster-wide, the event regards the Dynamic plugin in the system namespace
+// instead of any pod, and is only recorded on transitions.
+func (ds *DynamicScheduler) recordSafetyValveEvent(eventType, reason, note string, args ...interface{}) {
+	if ds.handle == nil || ds.handle.EventRecorder() == nil {
+		return
+	}
+
+	regarding := &v1.ObjectReference{
+		Kind:      "DynamicSchedulerPolicy",
+		Namespace: utils.GetSystemNamespace(),
+		Name:      Name,
+	}
+	ds.handle.EventRecorder().Eventf(regarding, nil, eventType, reason, "SafetyValve", note, args...)
+}
+
+// countOverloadedNodes returns the number of nodes overloaded by predicates of the
+// spec and node pools, regardless of pods.
+func countOverloadedNodes(cache *nodeLoadCache, nodes []*v1.Node, spec policy.PolicySpec) int {
+	var overloaded int
+
+	nodePools := policy.NewNodePoolSelector(spec.NodePools)
+	for _, node := range nodes {
+		if isOverloaded(cache.get(node), policy.ApplyNodePool(spec, nodePools.Select(node.Labels))) {
+			overloaded++
+		}
+	}
+
+	return overloaded
+}
+
+// isOverloaded checks if the usage of the node exceeds the threshold of any predicate.
+func isOverloaded(load *nodeLoad, policySpec policy.PolicySpec) bool {
+	for _, predicatePolicy := range policySpec.Predicate {
+		activeDuration, err := policy.GetActiveDuration(policySpec.SyncPeriod, predicatePolicy.Name)
+		if err != nil || activeDuration == 0 {
+			continue
+		}
+
+		if explainPredicate(load, predicatePolicy, activeDuration).Overload {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ds *DynamicScheduler) isDegraded() bool {
+	return atomic.LoadInt32(&ds.degraded) == 1
+}
+
+func (ds *DynamicScheduler) setDegraded(degraded bool) {
+	var value int32
+	if degraded {
+		value = 1
+	}
+
+	atomic.StoreInt32(&ds.degraded, value)
+	metrics.DynamicFilterDegraded.Set(float64(value))
+}
@@ -0,0 +1,105 @@
+package dynamic
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestCheckSafetyValve(t *testing.T) {
+	timestamp := utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	// 3 of 4 nodes are overloaded.
+	for i, usage := range []string{"0.90000", "0.80000", "0.70000", "0.50000"} {
+		indexer.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("node-%d", i),
+			Annotations: map[string]string{"cpu_usage_avg_5m": usage + "," + timestamp},
+		}})
+	}
+
+	spec := policy.PolicySpec{
+		SyncPeriod: []policy.SyncPolicy{{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}}},
+		Predicate:  []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65}},
+	}
+
+	tests := []struct {
+		name         string
+		safetyValve  *policy.SafetyValvePolicy
+		wantDegraded bool
+	}{
+		{name: "no safety valve"},
+		{name: "ratio exceeded", safetyValve: &policy.SafetyValvePolicy{MaxOverloadedRatio: 0.5}, wantDegraded: true},
+		{name: "ratio not exceeded", safetyValve: &policy.SafetyValvePolicy{MaxOverloadedRatio: 0.75}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec.SafetyValve = tt.safetyValve
+			ds := &DynamicScheduler{
+				schedulerPolicy: &policy.DynamicSchedulerPolicy{Spec: spec},
+				loadCache:       newNodeLoadCache(),
+				nodeLister:      corelisters.NewNodeLister(indexer),
+			}
+
+			ds.checkSafetyValve()
+			if ds.isDegraded() != tt.wantDegraded {
+				t.Errorf("got degraded %t, want %t", ds.isDegraded(), tt.wantDegraded)
+			}
+		})
+	}
+}
+
+func TestCheckSafetyValveEvents(t *testing.T) {
+	timestamp := utils.FormatTimestamp(time.Now(), utils.RFC3339TimestampFormat)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "node-1",
+		Annotations: map[string]string{"cpu_usage_avg_5m": "0.90000," + timestamp},
+	}})
+
+	spec := policy.PolicySpec{
+		SyncPeriod:  []policy.SyncPolicy{{Name: "cpu_usage_avg_5m", Period: metav1.Duration{Duration: 3 * time.Minute}}},
+		Predicate:   []policy.PredicatePolicy{{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65}},
+		SafetyValve: &policy.SafetyValvePolicy{MaxOverloadedRatio: 0.5},
+	}
+
+	recorder := events.NewFakeRecorder(10)
+	ds := &DynamicScheduler{
+		handle:          &fakeHandle{recorder: recorder},
+		schedulerPolicy: &policy.DynamicSchedulerPolicy{Spec: spec},
+		loadCache:       newNodeLoadCache(),
+		nodeLister:      corelisters.NewNodeLister(indexer),
+	}
+
+	// the event is only recorded once while Filter stays degraded.
+	ds.checkSafetyValve()
+	ds.checkSafetyValve()
+	if got := len(recorder.Events); got != 1 {
+		t.Fatalf("got %d events while degraded, want 1", got)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "DynamicFilterDegraded") {
+		t.Errorf("got event %q, want DynamicFilterDegraded", event)
+	}
+
+	ds.schedulerPolicy.Spec.Predicate[0].MaxLimitPecent = 0.95
+	ds.checkSafetyValve()
+	ds.checkSafetyValve()
+	if got := len(recorder.Events); got != 1 {
+		t.Fatalf("got %d events after resumed, want 1", got)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "DynamicFilterResumed") {
+		t.Errorf("got event %q, want DynamicFilterResumed", event)
+	}
+}
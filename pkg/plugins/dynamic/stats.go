@@ -2,165 +2,223 @@ package dynamic
 
 import (
 	"fmt"
-	"strconv"
-	"strings"
+	"math"
 	"time"
 
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
-
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 	"github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
 const (
-	// MinTimestampStrLength defines the min length of timestamp string.
-	MinTimestampStrLength = 5
 	// NodeHotValue is the key of hot value annotation.
 	NodeHotValue = "node_hot_value"
 	// DefautlHotVauleActivePeriod defines the validity period of nodes' hotvalue.
 	DefautlHotVauleActivePeriod = 5 * time.Minute
 	// ExtraActivePeriod gives extra active time to the annotation.
-	ExtraActivePeriod = 5 * time.Minute
+	ExtraActivePeriod = policy.ExtraActivePeriod
 )
 
-// inActivePeriod judges if node annotation with this timestamp is effective.
-func inActivePeriod(updatetimeStr string, activeDuration time.Duration) bool {
-	if len(updatetimeStr) < MinTimestampStrLength {
-		klog.Errorf("[crane] illegel timestamp: %s", updatetimeStr)
-		return false
+// getUsageOrLastKnown returns the usage of the metric, or the last known usage of
+// expired data if action is UseLastKnown. The action actually taken is returned
+// along with the error if the usage is not available.
+func getUsageOrLastKnown(load *nodeLoad, key string, activeDuration time.Duration, action, defaultAction policy.MissingDataAction) (float64, policy.MissingDataAction, error) {
+	usage, err := load.getResourceUsage(key, activeDuration)
+	if err == nil {
+		return usage, "", nil
 	}
 
-	originUpdateTime, err := time.ParseInLocation(utils.TimeFormat, updatetimeStr, utils.GetLocation())
-	if err != nil {
-		klog.Errorf("[crane] failed to parse timestamp: %v", err)
-		return false
+	if action == "" {
+		return 0, defaultAction, err
 	}
 
-	now, updatetime := time.Now(), originUpdateTime.Add(activeDuration)
-
-	if now.Before(updatetime) {
-		return true
+	if action == policy.MissingDataUseLastKnown {
+		if expired, ok := err.(*utils.ExpiredError); ok {
+			return expired.Value, action, err
+		}
+		return 0, defaultAction, err
 	}
 
-	return false
+	return 0, action, err
 }
 
-func getResourceUsage(anno map[string]string, key string, activeDuration time.Duration) (float64, error) {
-	usedstr, ok := anno[key]
-	if !ok {
-		return 0, fmt.Errorf("key[%s] not found", usedstr)
-	}
-
-	usedSlice := strings.Split(usedstr, ",")
-	if len(usedSlice) != 2 {
-		return 0, fmt.Errorf("illegel value: %s", usedstr)
-	}
+// explainPriority scores the node by the usage of the metric of priorityPolicy.
+func explainPriority(load *nodeLoad, priorityPolicy policy.PriorityPolicy, syncPeriod []policy.SyncPolicy) PriorityExplanation {
+	e := PriorityExplanation{Name: priorityPolicy.Name, Weight: priorityPolicy.Weight}
 
-	if !inActivePeriod(usedSlice[1], activeDuration) {
-		return 0, fmt.Errorf("timestamp[%s] is expired", usedstr)
+	activeDuration, err := policy.GetActiveDuration(syncPeriod, priorityPolicy.Name)
+	if err != nil || activeDuration == 0 {
+		e.Error = fmt.Sprintf("failed to get the active duration of resource[%s]: %v, while the actual value is %v", priorityPolicy.Name, err, activeDuration)
+		e.MissingDataAction = policy.MissingDataPenalizeScore
+		return e
 	}
 
-	UsedValue, err := strconv.ParseFloat(usedSlice[0], 64)
+	usage, action, err := getUsageOrLastKnown(load, priorityPolicy.Name, activeDuration, priorityPolicy.MissingDataAction, policy.MissingDataPenalizeScore)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse float[%s]", usedSlice[0])
+		e.Stale, e.Error, e.MissingDataAction = isExpired(err), err.Error(), action
+		if action != policy.MissingDataUseLastKnown {
+			return e
+		}
 	}
 
-	if UsedValue < 0 {
-		return 0, fmt.Errorf("illegel value: %s", usedstr)
-	}
+	e.Anticipated = load.anticipated[priorityPolicy.Name]
+	e.Usage = usage + e.Anticipated
+	e.Score = (1. - e.Usage) * priorityPolicy.Weight * float64(framework.MaxNodeScore)
 
-	return UsedValue, nil
+	return e
 }
 
-func getScore(anno map[string]string, priorityPolicy policy.PriorityPolicy, syncPeriod []policy.SyncPolicy) (float64, error) {
-	activeDuration, err := getActiveDuration(syncPeriod, priorityPolicy.Name)
-	if err != nil || activeDuration == 0 {
-		return 0, fmt.Errorf("failed to get the active duration of resource[%s]: %v, while the actual value is %v", priorityPolicy.Name, err, activeDuration)
-	}
+// explainPredicate judges if the usage of the metric exceeds the threshold of predicatePolicy.
+func explainPredicate(load *nodeLoad, predicatePolicy policy.PredicatePolicy, activeDuration time.Duration) PredicateExplanation {
+	e := PredicateExplanation{Name: predicatePolicy.Name, Threshold: predicatePolicy.MaxLimitPecent}
 
-	usage, err := getResourceUsage(anno, priorityPolicy.Name, activeDuration)
+	usage, action, err := getUsageOrLastKnown(load, predicatePolicy.Name, activeDuration, predicatePolicy.MissingDataAction, policy.MissingDataIgnore)
 	if err != nil {
-		return 0, err
+		e.Stale, e.Error, e.MissingDataAction = isExpired(err), err.Error(), action
+		if action != policy.MissingDataUseLastKnown {
+			return e
+		}
 	}
 
-	score := (1. - usage) * priorityPolicy.Weight * float64(framework.MaxNodeScore)
+	e.Anticipated, e.Incoming = load.anticipated[predicatePolicy.Name], load.incoming[predicatePolicy.Name]
+	e.Usage = usage + e.Anticipated + e.Incoming
+	// threshold was set as 0 means that the filter according to this metric is useless.
+	e.Overload = predicatePolicy.MaxLimitPecent != 0 && e.Usage > predicatePolicy.MaxLimitPecent
 
-	return score, nil
+	return e
 }
 
-func isOverLoad(name string, anno map[string]string, predicatePolicy policy.PredicatePolicy, activeDuration time.Duration) bool {
-	usage, err := getResourceUsage(anno, predicatePolicy.Name, activeDuration)
-	if err != nil {
-		klog.Errorf("[crane] can not get the usage of resource[%s] from node[%s]'s annotation: %v", predicatePolicy.Name, name, err)
-		return false
+// filterByPredicate returns the reason why the node is rejected by predicatePolicy,
+// or an empty string if the node passes.
+func filterByPredicate(name string, load *nodeLoad, predicatePolicy policy.PredicatePolicy, activeDuration time.Duration) string {
+	e := explainPredicate(load, predicatePolicy, activeDuration)
+	if e.Error != "" {
+		klog.Errorf("[crane] can not get the usage of resource[%s] from node[%s]'s annotation: %s, take action %s", predicatePolicy.Name, name, e.Error, e.MissingDataAction)
+		countMissingLoadData("filter", e.Name, e.Stale, e.MissingDataAction)
 	}
 
-	// threshold was set as 0 means that the filter according to this metric is useless.
 	if predicatePolicy.MaxLimitPecent == 0 {
-		klog.V(4).Info("[crane] ignore the filter of resource[%s] for MaxLimitPecent was set as 0")
-		return false
+		klog.V(4).Infof("[crane] ignore the filter of resource[%s] for MaxLimitPecent was set as 0", predicatePolicy.Name)
 	}
 
-	if usage > predicatePolicy.MaxLimitPecent {
-		return true
+	reason := e.rejectReason(name)
+	if e.Overload {
+		metrics.DynamicFilterRejections.WithLabelValues(e.Name, "overload").Inc()
+	} else if reason != "" {
+		metrics.DynamicFilterRejections.WithLabelValues(e.Name, "missing_data").Inc()
 	}
 
-	return false
+	return reason
 }
 
-func getNodeScore(name string, anno map[string]string, policySpec policy.PolicySpec) int {
-
-	lenPriorityPolicyList := len(policySpec.Priority)
-	if lenPriorityPolicyList == 0 {
-		klog.Warningf("[crane] no priority policy exists, all nodes scores 0.")
-		return 0
-	}
-
+// explainPriorities scores the node by the weighted average of all priority scores.
+func explainPriorities(load *nodeLoad, policySpec policy.PolicySpec) ([]PriorityExplanation, int) {
+	var explanations []PriorityExplanation
 	var score, weight float64
 
 	for _, priorityPolicy := range policySpec.Priority {
+		e := explainPriority(load, priorityPolicy, policySpec.SyncPeriod)
 
-		priorityScore, err := getScore(anno, priorityPolicy, policySpec.SyncPeriod)
-		if err != nil {
-			klog.Errorf("[crane] failed to get node 's score: %v", name, priorityPolicy.Name, score)
+		if e.MissingDataAction != policy.MissingDataIgnore {
+			weight += priorityPolicy.Weight
+			score += e.Score
 		}
 
-		weight += priorityPolicy.Weight
-		score += priorityScore
+		explanations = append(explanations, e)
 	}
 
-	finnalScore := int(score / weight)
+	if weight == 0 {
+		return explanations, 0
+	}
 
-	return finnalScore
+	for i := range explanations {
+		if explanations[i].MissingDataAction != policy.MissingDataIgnore {
+			explanations[i].Contribution = explanations[i].Score / weight
+		}
+	}
+
+	return explanations, int(score / weight)
 }
 
-func getActiveDuration(syncPeriodList []policy.SyncPolicy, name string) (time.Duration, error) {
-	for _, period := range syncPeriodList {
-		if period.Name == name {
-			if period.Period.Duration != 0 {
-				return period.Period.Duration + ExtraActivePeriod, nil
-			}
+// getNodeScore returns the weighted average of priority scores of the node, and
+// whether the node should get the min score for its missing data.
+func getNodeScore(name string, load *nodeLoad, policySpec policy.PolicySpec) (int, bool) {
+	var penalized bool
+
+	for _, predicatePolicy := range policySpec.Predicate {
+		if predicatePolicy.MissingDataAction != policy.MissingDataPenalizeScore {
+			continue
+		}
+
+		activeDuration, err := policy.GetActiveDuration(policySpec.SyncPeriod, predicatePolicy.Name)
+		if err != nil || activeDuration == 0 {
+			continue
+		}
+
+		if e := explainPredicate(load, predicatePolicy, activeDuration); e.MissingDataAction == policy.MissingDataPenalizeScore {
+			countMissingLoadData("score", e.Name, e.Stale, e.MissingDataAction)
+			penalized = true
 		}
 	}
 
-	return 0, fmt.Errorf("failed to get the active duration")
+	lenPriorityPolicyList := len(policySpec.Priority)
+	if lenPriorityPolicyList == 0 {
+		klog.Warningf("[crane] no priority policy exists, all nodes scores 0.")
+		return 0, penalized
+	}
+
+	explanations, score := explainPriorities(load, policySpec)
+	for _, e := range explanations {
+		if e.Error != "" {
+			klog.Errorf("[crane] failed to get node[%s]'s score of %s: %s, take action %s", name, e.Name, e.Error, e.MissingDataAction)
+			countMissingLoadData("score", e.Name, e.Stale, e.MissingDataAction)
+			penalized = penalized || e.MissingDataAction == policy.MissingDataReject
+		}
+	}
+
+	return score, penalized
 }
 
-func getNodeHotValue(node *v1.Node) float64 {
-	anno := node.ObjectMeta.Annotations
-	if anno == nil {
-		return 0
+func countMissingLoadData(stage, metric string, stale bool, action policy.MissingDataAction) {
+	reason := "missing"
+	if stale {
+		reason = "stale"
 	}
 
-	hotvalue, err := getResourceUsage(anno, NodeHotValue, DefautlHotVauleActivePeriod)
+	metrics.MissingLoadData.WithLabelValues(stage, metric, reason, string(action)).Inc()
+}
+
+func getNodeHotValue(name string, load *nodeLoad) float64 {
+	hotvalue, err := load.getResourceUsage(NodeHotValue, DefautlHotVauleActivePeriod)
 	if err != nil {
 		return 0
 	}
 
-	klog.V(4).Infof("[crane] Node[%s]'s hotvalue is %f\n", node.Name, hotvalue)
+	klog.V(4).Infof("[crane] Node[%s]'s hotvalue is %f\n", name, hotvalue)
 
 	return hotvalue
 }
+
+// getHotValuePenalty returns the score deducted for the hot value, which is already
+// scaled by the penalty factors of hot value policies.
+func getHotValuePenalty(hotValue float64) int {
+	return int(math.Round(hotValue))
+}
+
+// getFinalScore deducts the hot value penalty from the score, and normalizes it
+// into the range of node scores.
+func getFinalScore(score, hotValuePenalty int, penalized bool) int64 {
+	if penalized {
+		return framework.MinNodeScore
+	}
+
+	return utils.NormalizeScore(int64(score-hotValuePenalty), framework.MaxNodeScore, framework.MinNodeScore)
+}
+
+func isExpired(err error) bool {
+	_, ok := err.(*utils.ExpiredError)
+	return ok
+}
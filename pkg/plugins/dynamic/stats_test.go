@@ -0,0 +1,55 @@
+package dynamic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
+	"github.com/gocrane/crane-scheduler/pkg/utils"
+)
+
+func TestFilterByPredicateMetrics(t *testing.T) {
+	metrics.Register()
+	metrics.MissingLoadData.Reset()
+	metrics.DynamicFilterRejections.Reset()
+
+	now := time.Now()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		Annotations: map[string]string{
+			"cpu_usage_avg_5m": "0.90000," + utils.FormatTimestamp(now, utils.RFC3339TimestampFormat),
+			"mem_usage_avg_5m": "0.50000," + utils.FormatTimestamp(now.Add(-time.Hour), utils.RFC3339TimestampFormat),
+		},
+	}}
+	load := newNodeLoadCache().get(node)
+
+	for _, predicatePolicy := range []policy.PredicatePolicy{
+		{Name: "cpu_usage_avg_5m", MaxLimitPecent: 0.65},
+		{Name: "mem_usage_avg_5m", MaxLimitPecent: 0.65, MissingDataAction: policy.MissingDataReject},
+		{Name: "disk_usage_avg_5m", MaxLimitPecent: 0.65},
+	} {
+		filterByPredicate(node.Name, load, predicatePolicy, 8*time.Minute)
+	}
+
+	expected := `
+# HELP crane_scheduler_dynamic_filter_rejections_total [ALPHA] Number of nodes rejected by the Dynamic plugin, by metric and reason.
+# TYPE crane_scheduler_dynamic_filter_rejections_total counter
+crane_scheduler_dynamic_filter_rejections_total{metric="cpu_usage_avg_5m",reason="overload"} 1
+crane_scheduler_dynamic_filter_rejections_total{metric="mem_usage_avg_5m",reason="missing_data"} 1
+# HELP crane_scheduler_missing_load_data_total [ALPHA] Number of times the load data of nodes is missing, malformed or stale, by stage, metric, reason and action taken.
+# TYPE crane_scheduler_missing_load_data_total counter
+crane_scheduler_missing_load_data_total{action="Ignore",metric="disk_usage_avg_5m",reason="missing",stage="filter"} 1
+crane_scheduler_missing_load_data_total{action="Reject",metric="mem_usage_avg_5m",reason="stale",stage="filter"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"crane_scheduler_dynamic_filter_rejections_total", "crane_scheduler_missing_load_data_total"); err != nil {
+		t.Error(err)
+	}
+}
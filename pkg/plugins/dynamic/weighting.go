@@ -0,0 +1,57 @@
+package dynamic
+
+import (
+	v1 "k8s.io/api/core/v1"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+// weighPriorities returns the priorities of the spec weighted for the pod on the node.
+// If PriorityWeighting is PodRequests, the weight of each priority with a resource is
+// scaled by the ratio of the pod's requests to the node allocatable of that resource,
+// divided by the mean ratio of all resources, so that the average scale is 1. Weights
+// are kept if the pod requests none of the resources.
+func weighPriorities(pod *v1.Pod, node *v1.Node, policySpec policy.PolicySpec) []policy.PriorityPolicy {
+	if policySpec.PriorityWeighting != policy.PriorityWeightingPodRequests {
+		return policySpec.Priority
+	}
+
+	ratios := map[string]float64{}
+	for _, p := range policySpec.Priority {
+		if p.Resource == "" {
+			continue
+		}
+		if _, ok := ratios[p.Resource]; ok {
+			continue
+		}
+
+		resourceName := v1.ResourceName(p.Resource)
+		allocatable, ok := node.Status.Allocatable[resourceName]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+
+		request := resourcehelper.GetResourceRequestQuantity(pod, resourceName)
+		ratios[p.Resource] = float64(request.MilliValue()) / float64(allocatable.MilliValue())
+	}
+
+	var sum float64
+	for _, ratio := range ratios {
+		sum += ratio
+	}
+	if sum == 0 {
+		return policySpec.Priority
+	}
+	mean := sum / float64(len(ratios))
+
+	priorities := make([]policy.PriorityPolicy, len(policySpec.Priority))
+	for i, p := range policySpec.Priority {
+		priorities[i] = p
+		if ratio, ok := ratios[p.Resource]; ok {
+			priorities[i].Weight = p.Weight * ratio / mean
+		}
+	}
+
+	return priorities
+}
@@ -0,0 +1,77 @@
+package dynamic
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/policy"
+)
+
+func TestWeighPriorities(t *testing.T) {
+	node := &v1.Node{Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("16Gi"),
+	}}}
+
+	newPod := func(requests v1.ResourceList) *v1.Pod {
+		return &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+			Resources: v1.ResourceRequirements{Requests: requests},
+		}}}}
+	}
+
+	policySpec := policy.PolicySpec{
+		PriorityWeighting: policy.PriorityWeightingPodRequests,
+		Priority: []policy.PriorityPolicy{
+			{Name: "cpu_usage_avg_5m", Weight: 1, Resource: "cpu"},
+			{Name: "mem_usage_avg_5m", Weight: 1, Resource: "memory"},
+			{Name: "net_usage_avg_5m", Weight: 0.5},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		weighting   policy.PriorityWeighting
+		wantWeights []float64
+	}{
+		{
+			name: "memory-heavy pod",
+			// requests 1/8 of cpus and 3/8 of memory.
+			pod:         newPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("6Gi")}),
+			weighting:   policy.PriorityWeightingPodRequests,
+			wantWeights: []float64{0.5, 1.5, 0.5},
+		},
+		{
+			name:        "pod without requests",
+			pod:         newPod(nil),
+			weighting:   policy.PriorityWeightingPodRequests,
+			wantWeights: []float64{1, 1, 0.5},
+		},
+		{
+			name:        "static weighting",
+			pod:         newPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}),
+			weighting:   policy.PriorityWeightingStatic,
+			wantWeights: []float64{1, 1, 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := policySpec
+			spec.PriorityWeighting = tt.weighting
+
+			priorities := weighPriorities(tt.pod, node, spec)
+			for i, p := range priorities {
+				if p.Weight != tt.wantWeights[i] {
+					t.Errorf("got weight %f of %s, want %f", p.Weight, p.Name, tt.wantWeights[i])
+				}
+			}
+		})
+	}
+
+	if policySpec.Priority[0].Weight != 1 {
+		t.Errorf("weights of the policy spec are modified")
+	}
+}
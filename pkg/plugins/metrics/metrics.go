@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// CraneSubsystem is the subsystem of metrics exposed by crane plugins.
+	CraneSubsystem = "crane_scheduler"
+)
+
+var (
+	// MissingLoadData counts how often the load data of nodes is not available,
+	// by the stage, the metric, the reason and the action taken.
+	MissingLoadData = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "missing_load_data_total",
+			Help:           "Number of times the load data of nodes is missing, malformed or stale, by stage, metric, reason and action taken.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"stage", "metric", "reason", "action"})
+
+	// DynamicFilterRejections counts nodes rejected by the Dynamic plugin.
+	DynamicFilterRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "dynamic_filter_rejections_total",
+			Help:           "Number of nodes rejected by the Dynamic plugin, by metric and reason.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"metric", "reason"})
+
+	// DynamicNodeScore is the distribution of final node scores given by the Dynamic plugin.
+	DynamicNodeScore = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "dynamic_node_score",
+			Help:           "Final node scores given by the Dynamic plugin.",
+			Buckets:        metrics.LinearBuckets(0, 10, 11),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// DynamicHotValuePenalty is the distribution of hot value penalties applied to node scores.
+	DynamicHotValuePenalty = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "dynamic_hot_value_penalty",
+			Help:           "Hot value penalties deducted from node scores by the Dynamic plugin, only observed when not zero.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 8),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// DynamicOverloadedNodes is the number of nodes overloaded by predicates of the Dynamic plugin.
+	DynamicOverloadedNodes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "dynamic_overloaded_nodes",
+			Help:           "Number of nodes overloaded by predicates of the Dynamic plugin, only checked if the safety valve is set.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// DynamicFilterDegraded is 1 if Filter of the Dynamic plugin is degraded to score-only mode.
+	DynamicFilterDegraded = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "dynamic_filter_degraded",
+			Help:           "Whether Filter of the Dynamic plugin is degraded to score-only mode for too many nodes overloaded.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// TopologyLookupFailures counts failures to get NodeResourceTopology of nodes.
+	TopologyLookupFailures = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "topology_nrt_lookup_failures_total",
+			Help:           "Number of failures to get NodeResourceTopology of nodes in the NodeResourceTopologyMatch plugin.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// TopologyNUMAInsufficient counts nodes rejected for no NUMA node has sufficient resources.
+	TopologyNUMAInsufficient = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "topology_numa_insufficient_total",
+			Help:           "Number of nodes rejected by the NodeResourceTopologyMatch plugin for insufficient resources of NUMA nodes.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// TopologyAssumedPods is the number of pods in the assumed pod topology cache.
+	TopologyAssumedPods = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "topology_assumed_pods",
+			Help:           "Number of pods in the assumed pod topology cache of the NodeResourceTopologyMatch plugin.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	// TopologyPreBindDuration is the latency of patching topology results to pods at PreBind.
+	TopologyPreBindDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      CraneSubsystem,
+			Name:           "topology_prebind_patch_duration_seconds",
+			Help:           "Latency of patching topology results to pods in the NodeResourceTopologyMatch plugin, by result.",
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"result"})
+
+	metricsList = []metrics.Registerable{
+		MissingLoadData,
+		DynamicFilterRejections,
+		DynamicNodeScore,
+		DynamicHotValuePenalty,
+		DynamicOverloadedNodes,
+		DynamicFilterDegraded,
+		TopologyLookupFailures,
+		TopologyNUMAInsufficient,
+		TopologyAssumedPods,
+		TopologyPreBindDuration,
+	}
+)
+
+var registerMetrics sync.Once
+
+// SinceInSeconds gets the time since the specified start in seconds.
+func SinceInSeconds(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// Register registers crane plugin metrics to the legacy registry, which is served
+// by the scheduler at /metrics.
+func Register() {
+	registerMetrics.Do(func() {
+		for _, metric := range metricsList {
+			legacyregistry.MustRegister(metric)
+		}
+	})
+}
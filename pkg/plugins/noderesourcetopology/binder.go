@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	corev1 "k8s.io/api/core/v1"
@@ -13,6 +14,8 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	topologyv1alpha1 "github.com/gocrane/api/topology/v1alpha1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 )
 
 // PreBind writes pod topology result annotations using the k8s client.
@@ -56,10 +59,13 @@ func (tm *TopologyMatch) PreBind(
 		return framework.AsStatus(fmt.Errorf("failed to create merge patch: %v", err))
 	}
 
+	startTime := time.Now()
 	_, err = tm.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name,
 		types.MergePatchType, patchBytes, metav1.PatchOptions{})
 	if err != nil {
+		metrics.TopologyPreBindDuration.WithLabelValues("error").Observe(metrics.SinceInSeconds(startTime))
 		return framework.AsStatus(err)
 	}
+	metrics.TopologyPreBindDuration.WithLabelValues("success").Observe(metrics.SinceInSeconds(startTime))
 	return nil
 }
@@ -12,6 +12,8 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	topologyv1alpha1 "github.com/gocrane/api/topology/v1alpha1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 )
 
 var (
@@ -65,6 +67,7 @@ func (c *podTopologyCacheImpl) AssumePod(pod *corev1.Pod, zone topologyv1alpha1.
 	dl := time.Now().Add(c.ttl)
 	c.podTopology[key] = zone
 	c.podTopologyTTL[key] = &dl
+	metrics.TopologyAssumedPods.Set(float64(len(c.podTopology)))
 	return nil
 }
 
@@ -131,5 +134,6 @@ func (c *podTopologyCacheImpl) cleanupAssumedPods(now time.Time) {
 func (c *podTopologyCacheImpl) removePod(key string) {
 	delete(c.podTopology, key)
 	delete(c.podTopologyTTL, key)
+	metrics.TopologyAssumedPods.Set(float64(len(c.podTopology)))
 	klog.V(4).Infof("Finished binding for pod %v. Can be expired.", key)
 }
@@ -0,0 +1,50 @@
+package noderesourcetopology
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	topologyv1alpha1 "github.com/gocrane/api/topology/v1alpha1"
+
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
+)
+
+func TestPodTopologyCacheAssumedPodsGauge(t *testing.T) {
+	metrics.Register()
+
+	cache := &podTopologyCacheImpl{
+		ttl:            time.Minute,
+		podTopology:    make(map[string]topologyv1alpha1.ZoneList),
+		podTopologyTTL: make(map[string]*time.Time),
+	}
+
+	assertAssumedPods := func(want float64) {
+		t.Helper()
+		if got, err := testutil.GetGaugeMetricValue(metrics.TopologyAssumedPods); err != nil || got != want {
+			t.Errorf("got assumed pods %v and error %v, want %v", got, err, want)
+		}
+	}
+
+	pods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1", UID: "pod-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2", UID: "pod-2"}},
+	}
+	for _, pod := range pods {
+		if err := cache.AssumePod(pod, topologyv1alpha1.ZoneList{}); err != nil {
+			t.Fatalf("failed to assume pod %s: %v", pod.Name, err)
+		}
+	}
+	assertAssumedPods(2)
+
+	if err := cache.ForgetPod(pods[0]); err != nil {
+		t.Fatalf("failed to forget pod: %v", err)
+	}
+	assertAssumedPods(1)
+
+	cache.cleanupAssumedPods(time.Now().Add(2 * time.Minute))
+	assertAssumedPods(0)
+}
@@ -8,6 +8,7 @@ import (
 
 	topologyv1alpha1 "github.com/gocrane/api/topology/v1alpha1"
 
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 	"github.com/gocrane/crane-scheduler/pkg/utils"
 )
 
@@ -63,6 +64,7 @@ func (tm *TopologyMatch) Filter(
 
 	nrt, err := tm.lister.Get(nodeInfo.Node().Name)
 	if err != nil {
+		metrics.TopologyLookupFailures.Inc()
 		return framework.NewStatus(framework.Unschedulable, ErrReasonFailedToGetNRT)
 	}
 	// let kubelet handle cpuset
@@ -116,6 +118,7 @@ func (tm *TopologyMatch) filterNUMANodeResource(state *stateData, nw *nodeWrappe
 	}
 
 	if len(res) == 0 {
+		metrics.TopologyNUMAInsufficient.Inc()
 		return framework.NewStatus(framework.Unschedulable, ErrReasonNUMAResourceNotEnough)
 	}
 	nw.numaNodes = res
@@ -17,6 +17,7 @@ import (
 	topologyv1alpha1 "github.com/gocrane/api/topology/v1alpha1"
 
 	"github.com/gocrane/crane-scheduler/pkg/plugins/apis/config"
+	"github.com/gocrane/crane-scheduler/pkg/plugins/metrics"
 )
 
 const (
@@ -35,6 +36,8 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		return nil, fmt.Errorf("want args to be of type NodeResourceTopologyMatchArgs, got %T", args)
 	}
 
+	metrics.Register()
+
 	ctx := context.TODO()
 	client, err := topologyclientset.NewForConfig(handle.KubeConfig())
 	if err != nil {
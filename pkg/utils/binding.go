@@ -1,4 +1,4 @@
-package annotator
+package utils
 
 import (
 	"container/heap"
@@ -77,23 +77,64 @@ func (br *BindingRecords) AddBinding(b *Binding) {
 	heap.Push(br.bindings, b)
 }
 
-// GetLastNodeBindingCount caculates how many pods scheduled on specified node recently.
-func (br *BindingRecords) GetLastNodeBindingCount(node string, timeRange time.Duration) int {
+// RemoveBinding removes the Binding of the pod, and returns false if not found.
+func (br *BindingRecords) RemoveBinding(namespace, podName string) bool {
+	br.rw.Lock()
+	defer br.rw.Unlock()
+
+	for i, binding := range *br.bindings {
+		if binding.Namespace == namespace && binding.PodName == podName {
+			heap.Remove(br.bindings, i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Len returns the number of Bindings in BindingHeap.
+func (br *BindingRecords) Len() int {
+	br.rw.RLock()
+	defer br.rw.RUnlock()
+
+	return br.bindings.Len()
+}
+
+// GetGCTimeRange returns the time range of Bindings kept by BindingsGC.
+func (br *BindingRecords) GetGCTimeRange() time.Duration {
+	br.rw.RLock()
+	defer br.rw.RUnlock()
+
+	return br.gcTimeRange
+}
+
+// SetGCTimeRange changes the time range of Bindings kept by BindingsGC.
+func (br *BindingRecords) SetGCTimeRange(tr time.Duration) {
+	br.rw.Lock()
+	defer br.rw.Unlock()
+
+	br.gcTimeRange = tr
+}
+
+// GetLastNodeBindingAges returns ages of pods scheduled on specified node within timeRange.
+func (br *BindingRecords) GetLastNodeBindingAges(node string, timeRange time.Duration) []time.Duration {
 	br.rw.RLock()
 	defer br.rw.RUnlock()
 
-	cnt, timeline := 0, time.Now().UTC().Unix()-int64(timeRange.Seconds())
+	var ages []time.Duration
+	now := time.Now().UTC().Unix()
+	timeline := now - int64(timeRange.Seconds())
 
 	for _, binding := range *br.bindings {
 		if binding.Timestamp > timeline && binding.Node == node {
-			cnt++
+			ages = append(ages, time.Duration(now-binding.Timestamp)*time.Second)
 		}
 	}
 
 	klog.V(4).Infof("The total Binding count is %d, while node[%s] count is %d",
-		len(*br.bindings), node, cnt)
+		len(*br.bindings), node, len(ages))
 
-	return cnt
+	return ages
 }
 
 // BindingsGC recycles expired Bindings.
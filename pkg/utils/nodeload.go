@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// NodeLoadAnnotationKey is the key of the structured annotation, which holds
+	// all load metrics of a node in one JSON object.
+	NodeLoadAnnotationKey = "scheduler.crane.io/node-load"
+	// MinTimestampStrLength defines the min length of timestamp string.
+	MinTimestampStrLength = 5
+)
+
+// NodeLoad is the load data of a node stored in the structured annotation.
+type NodeLoad struct {
+	// Metrics maps metric names, including node_hot_value, to their latest samples.
+	Metrics map[string]MetricSample `json:"metrics"`
+}
+
+// MetricSample is a metric value with the time it was updated.
+type MetricSample struct {
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ParseNodeLoad decodes the structured node load annotation.
+func ParseNodeLoad(data string) (*NodeLoad, error) {
+	load := &NodeLoad{}
+	if err := json.Unmarshal([]byte(data), load); err != nil {
+		return nil, err
+	}
+
+	if load.Metrics == nil {
+		load.Metrics = map[string]MetricSample{}
+	}
+
+	return load, nil
+}
+
+// FormatNodeLoad encodes the node load as the value of the structured annotation.
+func FormatNodeLoad(load *NodeLoad) (string, error) {
+	data, err := json.Marshal(load)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// GetNodeLoadUsage returns the value of metric key if it is updated within activeDuration,
+// which is read from the structured load first if not nil, and then from the legacy
+// per-metric annotation. An *ExpiredError is returned if the value is out of date.
+func GetNodeLoadUsage(annotations map[string]string, structured *NodeLoad, key string, activeDuration time.Duration) (float64, error) {
+	if structured != nil {
+		if sample, ok := structured.Metrics[key]; ok {
+			if sample.Value < 0 {
+				return 0, fmt.Errorf("illegel value of %s: %f", key, sample.Value)
+			}
+			if time.Now().After(sample.Timestamp.Add(activeDuration)) {
+				return 0, &ExpiredError{Key: key, Timestamp: sample.Timestamp.Format(time.RFC3339), Value: sample.Value}
+			}
+			return sample.Value, nil
+		}
+	}
+
+	return getLegacyUsage(annotations, key, activeDuration)
+}
+
+// GetNodeUsage returns the value of metric key of the node if it is updated within
+// activeDuration, decoding the structured load annotation of the node if any.
+func GetNodeUsage(annotations map[string]string, key string, activeDuration time.Duration) (float64, error) {
+	var structured *NodeLoad
+	if data, ok := annotations[NodeLoadAnnotationKey]; ok {
+		if load, err := ParseNodeLoad(data); err == nil {
+			structured = load
+		}
+	}
+
+	return GetNodeLoadUsage(annotations, structured, key, activeDuration)
+}
+
+func getLegacyUsage(anno map[string]string, key string, activeDuration time.Duration) (float64, error) {
+	usedstr, ok := anno[key]
+	if !ok {
+		return 0, fmt.Errorf("key[%s] not found", key)
+	}
+
+	usedSlice := strings.Split(usedstr, ",")
+	if len(usedSlice) != 2 {
+		return 0, fmt.Errorf("illegel value: %s", usedstr)
+	}
+
+	UsedValue, err := strconv.ParseFloat(usedSlice[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse float[%s]", usedSlice[0])
+	}
+
+	if UsedValue < 0 {
+		return 0, fmt.Errorf("illegel value: %s", usedstr)
+	}
+
+	if !inActivePeriod(usedSlice[1], activeDuration) {
+		return 0, &ExpiredError{Key: key, Timestamp: usedSlice[1], Value: UsedValue}
+	}
+
+	return UsedValue, nil
+}
+
+// inActivePeriod judges if node annotation with this timestamp is effective.
+func inActivePeriod(updatetimeStr string, activeDuration time.Duration) bool {
+	if len(updatetimeStr) < MinTimestampStrLength {
+		klog.Errorf("illegel timestamp: %s", updatetimeStr)
+		return false
+	}
+
+	originUpdateTime, err := ParseTimestamp(updatetimeStr)
+	if err != nil {
+		klog.Errorf("failed to parse timestamp: %v", err)
+		return false
+	}
+
+	now, updatetime := time.Now(), originUpdateTime.Add(activeDuration)
+
+	if now.Before(updatetime) {
+		return true
+	}
+
+	return false
+}
+
+// ExpiredError means that the metric exists but is not updated within its active period.
+type ExpiredError struct {
+	Key       string
+	Timestamp string
+	// Value is the last known value of the metric.
+	Value float64
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("timestamp[%s] of %s is expired", e.Timestamp, e.Key)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampFormat is the format of timestamps in load annotations.
+type TimestampFormat string
+
+const (
+	// LegacyTimestampFormat formats local time of TZ zone with TimeFormat, which is
+	// ambiguous when the controller and scheduler run with different TZ values.
+	LegacyTimestampFormat TimestampFormat = "legacy"
+	// RFC3339TimestampFormat formats UTC time as RFC3339 with numeric offset, e.g.
+	// 2022-08-01T08:00:00+00:00. The "Z" suffix is left to the legacy format, so
+	// that the two formats can be told apart.
+	RFC3339TimestampFormat TimestampFormat = "rfc3339"
+	// UnixTimestampFormat formats time as Unix seconds.
+	UnixTimestampFormat TimestampFormat = "unix"
+
+	// RFC3339NumericOffset is the layout of RFC3339TimestampFormat.
+	RFC3339NumericOffset = "2006-01-02T15:04:05-07:00"
+)
+
+// FormatTimestamp formats the time in the given format.
+func FormatTimestamp(t time.Time, format TimestampFormat) string {
+	switch format {
+	case RFC3339TimestampFormat:
+		return t.UTC().Format(RFC3339NumericOffset)
+	case UnixTimestampFormat:
+		return strconv.FormatInt(t.Unix(), 10)
+	default:
+		loc := GetLocation()
+		if loc == nil {
+			return t.Format(TimeFormat)
+		}
+		return t.In(loc).Format(TimeFormat)
+	}
+}
+
+// ParseTimestamp parses timestamps in any of the supported formats. Timestamps
+// ending with "Z" are regarded as legacy ones in the local zone of TZ.
+func ParseTimestamp(s string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	if strings.HasSuffix(s, "Z") {
+		loc := GetLocation()
+		if loc == nil {
+			loc = time.UTC
+		}
+		return time.ParseInLocation(TimeFormat, s, loc)
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timestamp format: %s", s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAndParseTimestamp(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	for _, format := range []TimestampFormat{LegacyTimestampFormat, RFC3339TimestampFormat, UnixTimestampFormat} {
+		t.Run(string(format), func(t *testing.T) {
+			s := FormatTimestamp(now, format)
+
+			got, err := ParseTimestamp(s)
+			if err != nil {
+				t.Fatalf("failed to parse %s: %v", s, err)
+			}
+			if !got.Equal(now) {
+				t.Errorf("parsed %s as %v, want %v", s, got, now)
+			}
+		})
+	}
+}
+
+func TestParseTimestampAcrossTimeZones(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	// written by a controller running in Asia/Shanghai.
+	t.Setenv("TZ", "Asia/Shanghai")
+	legacy := FormatTimestamp(now, LegacyTimestampFormat)
+	rfc3339, unix := FormatTimestamp(now, RFC3339TimestampFormat), FormatTimestamp(now, UnixTimestampFormat)
+
+	// read by a scheduler running in Asia/Shanghai as well.
+	got, err := ParseTimestamp(legacy)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", legacy, err)
+	}
+	if !got.Equal(now) {
+		t.Errorf("parsed legacy %s in the same zone as %v, want %v", legacy, got, now)
+	}
+
+	// read by a scheduler running in America/New_York.
+	t.Setenv("TZ", "America/New_York")
+	for _, s := range []string{rfc3339, unix} {
+		got, err := ParseTimestamp(s)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", s, err)
+		}
+		if !got.Equal(now) {
+			t.Errorf("parsed %s as %v, want %v", s, got, now)
+		}
+	}
+
+	// the legacy format carries no zone, so it is shifted by the offset between zones.
+	got, err = ParseTimestamp(legacy)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", legacy, err)
+	}
+	_, shanghaiOffset := now.In(mustLoadLocation(t, "Asia/Shanghai")).Zone()
+	_, newYorkOffset := now.In(mustLoadLocation(t, "America/New_York")).Zone()
+	if want := now.Add(time.Duration(shanghaiOffset-newYorkOffset) * time.Second); !got.Equal(want) {
+		t.Errorf("parsed legacy %s in another zone as %v, want %v", legacy, got, want)
+	}
+
+	if _, err := ParseTimestamp("yesterday"); err == nil {
+		t.Errorf("expect error for illegal timestamp")
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %v", name, err)
+	}
+	return loc
+}
@@ -24,12 +24,7 @@ func IsDaemonsetPod(pod *corev1.Pod) bool {
 }
 
 func GetLocalTime() string {
-	loc := GetLocation()
-	if loc == nil {
-		time.Now().Format(TimeFormat)
-	}
-
-	return time.Now().In(loc).Format(TimeFormat)
+	return FormatTimestamp(time.Now(), LegacyTimestampFormat)
 }
 
 func GetLocation() *time.Location {